@@ -0,0 +1,96 @@
+package allocation
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+)
+
+var ErrInsufficientCapacity = errors.New("data centers cannot hold all fragments")
+
+// DataCenter is a data center which can hold a limited number of fragments.
+type DataCenter struct {
+	Risk     int               // base risk of the data center
+	Capacity int               // maximum number of fragments the data center can hold
+	Labels   map[string]string // e.g. "region": "eu-west", "provider": "aws"
+}
+
+// validateDataCenters checks the data centers and the number of fragments like validate.
+func validateDataCenters(dataCenters []DataCenter, fragments int) error {
+	risks := make([]int, len(dataCenters))
+	for i, dc := range dataCenters {
+		risks[i] = dc.Risk
+	}
+
+	return validate(risks, fragments)
+}
+
+// newCandidates validates the data centers and returns the Allocation without fragments
+// with the data centers which can hold fragments.
+// Returns ErrInsufficientCapacity if the total capacity is below the number of fragments.
+func newCandidates(dataCenters []DataCenter, fragments int) (Allocation, []dataCenter, error) {
+	if err := validateDataCenters(dataCenters, fragments); err != nil {
+		return Allocation{}, nil, err
+	}
+
+	allocation := newAllocation(len(dataCenters))
+
+	total := 0
+	candidates := make([]dataCenter, 0, len(dataCenters))
+	for i, dc := range dataCenters {
+		allocation.Risks[i] = Risk{Base: dc.Risk}
+		if dc.Capacity <= 0 {
+			continue
+		}
+
+		// the sum is capped, so large capacities cannot overflow it
+		total += min(dc.Capacity, fragments-total)
+		candidates = append(candidates, dataCenter{baseRisk: dc.Risk, capacity: dc.Capacity, index: i})
+	}
+	if total < fragments {
+		return Allocation{}, nil, fmt.Errorf("%w: capacity %d, fragments %d", ErrInsufficientCapacity, total, fragments)
+	}
+
+	return allocation, candidates, nil
+}
+
+// DistributeWithCapacity distributes the fragments among the data centers the same way as Distribute,
+// without storing more fragments in a data center than its capacity.
+// A full data center is removed from the heap, so the next fragments go to the data center
+// with the next lowest risk.
+//
+// Parameters:
+//   - dataCenters: the data centers with their base risks and capacities.
+//   - fragments: the number of fragments to distribute.
+//
+// Returns:
+//   - The Allocation, indexed like dataCenters.
+//   - ErrEmptyDataCenters, ErrNonPositiveRisk or ErrNegativeFragments for invalid input.
+//   - ErrInsufficientCapacity if the total capacity is below the number of fragments.
+func DistributeWithCapacity(dataCenters []DataCenter, fragments int) (Allocation, error) {
+	allocation, candidates, err := newCandidates(dataCenters, fragments)
+	if err != nil {
+		return Allocation{}, err
+	}
+	dataCentersHeap := newMinHeap(candidates)
+	full := make([]dataCenter, 0, len(candidates))
+
+	allocation.Bottleneck = 0
+	for i := 0; i < fragments; i++ {
+		dc := dataCentersHeap.peekDataCenter()
+		allocation.Bottleneck = dc.index
+		dc.IncreaseRisk()
+
+		if dc.full() {
+			// the full data center takes no more fragments
+			full = append(full, heap.Pop(dataCentersHeap).(dataCenter))
+			continue
+		}
+		heap.Fix(dataCentersHeap, 0)
+	}
+
+	allocation.record(*dataCentersHeap)
+	allocation.record(full)
+
+	return allocation, nil
+}
@@ -0,0 +1,121 @@
+package allocation
+
+import (
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestDistributeWithCapacity(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		dataCenters   []DataCenter
+		fragments     int
+		expCounts     []int
+		expRisks      []int
+		expBottleneck int
+		expErr        error
+	}{
+		{
+			desc:          "Success_CapacitiesNotReached",
+			dataCenters:   []DataCenter{{Risk: 10, Capacity: 5}, {Risk: 20, Capacity: 5}, {Risk: 30, Capacity: 5}},
+			fragments:     5,
+			expCounts:     []int{2, 2, 1},
+			expRisks:      []int{100, 400, 30},
+			expBottleneck: 1,
+		},
+		{
+			desc:          "Success_FullDataCenter",
+			dataCenters:   []DataCenter{{Risk: 10, Capacity: 1}, {Risk: 20, Capacity: 5}, {Risk: 30, Capacity: 5}},
+			fragments:     5,
+			expCounts:     []int{1, 2, 2},
+			expRisks:      []int{10, 400, 900},
+			expBottleneck: 2,
+		},
+		{
+			desc:          "Success_AllDataCentersFull",
+			dataCenters:   []DataCenter{{Risk: 10, Capacity: 1}, {Risk: 20, Capacity: 2}, {Risk: 30, Capacity: 2}},
+			fragments:     5,
+			expCounts:     []int{1, 2, 2},
+			expRisks:      []int{10, 400, 900},
+			expBottleneck: 2,
+		},
+		{
+			desc:          "BaseRiskOne_LimitedCapacity_ShouldSucceed",
+			dataCenters:   []DataCenter{{Risk: 10, Capacity: 5}, {Risk: 1, Capacity: 3}},
+			fragments:     5,
+			expCounts:     []int{2, 3},
+			expRisks:      []int{100, 1},
+			expBottleneck: 0,
+		},
+		{
+			desc:          "ZeroCapacity_ShouldSucceed",
+			dataCenters:   []DataCenter{{Risk: 2, Capacity: 0}, {Risk: 10, Capacity: 5}},
+			fragments:     2,
+			expCounts:     []int{0, 2},
+			expRisks:      []int{1, 100},
+			expBottleneck: 1,
+		},
+		{
+			desc:          "ZeroFragments_ShouldSucceed",
+			dataCenters:   []DataCenter{{Risk: 10, Capacity: 5}, {Risk: 20, Capacity: 5}},
+			fragments:     0,
+			expCounts:     []int{0, 0},
+			expRisks:      []int{1, 1},
+			expBottleneck: 0,
+		},
+		{
+			desc:        "EmptyDataCenters_ZeroFragments_ShouldFail",
+			dataCenters: []DataCenter{},
+			fragments:   0,
+			expErr:      ErrEmptyDataCenters,
+		},
+		{
+			desc:        "InsufficientCapacity_ShouldFail",
+			dataCenters: []DataCenter{{Risk: 10, Capacity: 1}, {Risk: 20, Capacity: 2}, {Risk: 30, Capacity: 1}},
+			fragments:   5,
+			expErr:      ErrInsufficientCapacity,
+		},
+		{
+			desc:        "EmptyDataCenters_ShouldFail",
+			dataCenters: []DataCenter{},
+			fragments:   1,
+			expErr:      ErrEmptyDataCenters,
+		},
+		{
+			desc:        "NonPositiveRisk_ShouldFail",
+			dataCenters: []DataCenter{{Risk: 10, Capacity: 5}, {Risk: 0, Capacity: 5}},
+			fragments:   1,
+			expErr:      ErrNonPositiveRisk,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			act, err := DistributeWithCapacity(tc.dataCenters, tc.fragments)
+			if tc.expErr != nil {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+			th.AssertNilError(t, err)
+			th.AssertEqualIntSlices(t, act.Counts, tc.expCounts)
+			th.AssertEqualIntSlices(t, riskInts(act.Risks), tc.expRisks)
+			th.AssertEqualInts(t, act.Bottleneck, tc.expBottleneck)
+		})
+	}
+}
+
+func TestDistributeWithCapacity_Unlimited_MatchesDistribute(t *testing.T) {
+	risks := []int{20, 10, 2, 15, 7}
+	dataCenters := make([]DataCenter, len(risks))
+	for i, risk := range risks {
+		dataCenters[i] = DataCenter{Risk: risk, Capacity: 1_000}
+	}
+
+	for _, fragments := range []int{0, 1, 10, 100, 1_000} {
+		exp, err := Distribute(risks, fragments)
+		th.AssertNilError(t, err)
+		act, err := DistributeWithCapacity(dataCenters, fragments)
+		th.AssertNilError(t, err)
+		th.AssertEqualInts(t, act.MaxRisk().Cmp(exp.MaxRisk()), 0)
+	}
+}
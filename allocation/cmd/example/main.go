@@ -8,7 +8,11 @@ import (
 func main() {
 	fragments := 5
 	risks := []int{10, 20, 30}
-	minimized := allocation.DistributeFragments(risks, fragments)
+	minimized, err := allocation.DistributeFragments(risks, fragments)
+	if err != nil {
+		fmt.Printf("error: %v\n", err)
+		return
+	}
 
 	fmt.Printf(
 		"minimized risk: %v (%v fragments, distributed across: %v data centers\n",
@@ -16,4 +20,16 @@ func main() {
 		fragments,
 		risks,
 	)
+
+	result, err := allocation.Distribute(risks, fragments)
+	if err != nil {
+		fmt.Printf("error: %v\n", err)
+		return
+	}
+	fmt.Printf(
+		"fragments per data center: %v, risks: %v, bottleneck: %v\n",
+		result.Counts,
+		result.Risks,
+		result.Bottleneck,
+	)
 }
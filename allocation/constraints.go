@@ -0,0 +1,442 @@
+package allocation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+var ErrInfeasibleConstraint = errors.New("constraint cannot be satisfied")
+
+// Constraints limit how the fragments can be spread across the labels of the data centers.
+// A data center without the label has the empty value of it.
+type Constraints struct {
+	MaxPerLabel map[string]int // label -> maximum number of fragments in data centers with the same value of the label
+	MinDistinct map[string]int // label -> minimum number of distinct values of the label holding fragments
+}
+
+// ConstraintError names the constraint which cannot be satisfied. It wraps ErrInfeasibleConstraint.
+type ConstraintError struct {
+	Label       string
+	Limit       int
+	MinDistinct bool // the MinDistinct constraint of the label, MaxPerLabel otherwise
+}
+
+func (e *ConstraintError) Error() string {
+	if e.MinDistinct {
+		return fmt.Sprintf("%v: at least %d distinct %q", ErrInfeasibleConstraint, e.Limit, e.Label)
+	}
+
+	return fmt.Sprintf("%v: at most %d fragments per %q", ErrInfeasibleConstraint, e.Limit, e.Label)
+}
+
+func (e *ConstraintError) Unwrap() error { return ErrInfeasibleConstraint }
+
+// DistributeWithConstraints distributes the fragments among the data centers honoring the capacities
+// and the constraints on the labels of the data centers, minimizing the maximum risk.
+//
+// The minimum is exact. A placement with the maximum risk at most R holds at most
+// floor(ln(R)/ln(baseRisk)) fragments in a data center, so R is binary searched in log space
+// like in DistributeBinarySearch and then among the exact risks around the float64 result,
+// checking whether there is a placement for every R. With the constraints on at most two labels
+// that is a flow: the fragments flow from the values of the first label through the data centers
+// to the values of the second one, MaxPerLabel limits the flow through a value and MinDistinct
+// needs one unit of it through that many values. With the constraints on more labels the counts
+// are searched with backtracking, exponential in the number of data centers in the worst case,
+// cut by the flow on the first two labels, the capacity left and the values left for MinDistinct.
+// A constraint which cannot be satisfied is found the same way: the constraints are dropped one by one
+// while the rest still have no placement, the error names a constraint of the remaining set.
+//
+// Parameters:
+//   - dataCenters: the data centers with their base risks, capacities and labels.
+//   - fragments: the number of fragments to distribute.
+//   - constraints: the limits on the labels.
+//
+// Returns:
+//   - The Allocation, indexed like dataCenters.
+//   - ErrEmptyDataCenters, ErrNonPositiveRisk or ErrNegativeFragments for invalid input.
+//   - ErrInsufficientCapacity if the total capacity is below the number of fragments.
+//   - *ConstraintError naming a constraint which cannot be satisfied with the others.
+func DistributeWithConstraints(dataCenters []DataCenter, fragments int, constraints Constraints) (Allocation, error) {
+	allocation, candidates, err := newCandidates(dataCenters, fragments)
+	if err != nil {
+		return Allocation{}, err
+	}
+
+	// the fragments each data center can hold at most
+	most := make([]int, len(dataCenters))
+	for _, dc := range candidates {
+		most[dc.index] = min(dc.capacity, fragments)
+	}
+
+	list := constraints.list()
+	if _, ok := place(dataCenters, most, fragments, list); !ok {
+		return Allocation{}, infeasibleError(dataCenters, most, fragments, list)
+	}
+
+	for i, count := range minimizeRisk(dataCenters, most, fragments, list) {
+		allocation.Counts[i] = count
+		allocation.Risks[i].Exponent = count
+	}
+	allocation.findBottleneck()
+
+	return allocation, nil
+}
+
+// constraint is one limit of Constraints.
+type constraint struct {
+	label       string
+	limit       int
+	minDistinct bool
+}
+
+// list returns the constraints sorted by label, MaxPerLabel before MinDistinct.
+func (c Constraints) list() []constraint {
+	list := make([]constraint, 0, len(c.MaxPerLabel)+len(c.MinDistinct))
+	for label, limit := range c.MaxPerLabel {
+		list = append(list, constraint{label: label, limit: limit})
+	}
+	for label, limit := range c.MinDistinct {
+		list = append(list, constraint{label: label, limit: limit, minDistinct: true})
+	}
+	slices.SortFunc(list, func(a, b constraint) int {
+		if c := strings.Compare(a.label, b.label); c != 0 {
+			return c
+		}
+		if a.minDistinct == b.minDistinct {
+			return 0
+		}
+		if a.minDistinct {
+			return 1
+		}
+		return -1
+	})
+
+	return list
+}
+
+// constraintLabels returns the sorted labels of the constraints.
+func constraintLabels(list []constraint) []string {
+	labels := make([]string, 0, len(list))
+	for _, c := range list {
+		labels = append(labels, c.label)
+	}
+
+	return slices.Compact(labels)
+}
+
+// place finds a placement of the fragments with at most caps[i] fragments in data center i,
+// honoring the constraints. Returns the counts, false if there is none.
+func place(dataCenters []DataCenter, caps []int, fragments int, list []constraint) ([]int, bool) {
+	labels := constraintLabels(list)
+	if len(labels) <= 2 {
+		return placeFlow(dataCenters, caps, fragments, list)
+	}
+
+	// the constraints on the first two labels alone are a quick check
+	firstTwo := slices.DeleteFunc(slices.Clone(list), func(c constraint) bool {
+		return c.label != labels[0] && c.label != labels[1]
+	})
+	if _, ok := placeFlow(dataCenters, caps, fragments, firstTwo); !ok {
+		return nil, false
+	}
+
+	return placeSearch(dataCenters, caps, fragments, list)
+}
+
+// placeFlow is place with the constraints on at most two labels.
+//
+// The network: source -> values of the first label -> data centers -> values of the second label -> sink,
+// with the fragments flowing from the sink back to the source. A value node is split in two,
+// the edge between the halves carries the MaxPerLabel limit. For MinDistinct the values also
+// get at most one unit through a collector node, whose edge to the source or the sink needs the minimum.
+func placeFlow(dataCenters []DataCenter, caps []int, fragments int, list []constraint) ([]int, bool) {
+	limits := make(map[string]int)
+	distinct := make(map[string]int)
+	for _, c := range list {
+		if c.minDistinct {
+			distinct[c.label] = c.limit
+		} else {
+			limits[c.label] = max(c.limit, 0)
+		}
+	}
+	limit := func(label string) int {
+		if l, ok := limits[label]; ok {
+			return l
+		}
+		return fragments
+	}
+
+	const source, sink = 0, 1
+	network := newFlowNetwork(2)
+	network.addEdge(sink, source, fragments, fragments)
+
+	labels := constraintLabels(list)
+	var first, second string
+	switch len(labels) {
+	case 1:
+		second = labels[0]
+	case 2:
+		first, second = labels[0], labels[1]
+	}
+
+	// value -> the node the data centers with the value are connected to
+	froms := make(map[string]int)
+	tos := make(map[string]int)
+	fromCollector, toCollector := -1, -1
+	if distinct[first] > 0 {
+		fromCollector = network.addNode()
+		network.addEdge(source, fromCollector, distinct[first], fragments)
+	}
+	if distinct[second] > 0 {
+		toCollector = network.addNode()
+		network.addEdge(toCollector, sink, distinct[second], fragments)
+	}
+
+	edges := make([]int, len(dataCenters))
+	for i, dc := range dataCenters {
+		edges[i] = -1
+		if caps[i] <= 0 {
+			continue
+		}
+
+		from := source
+		if first != "" {
+			value := dc.Labels[first]
+			if _, ok := froms[value]; !ok {
+				in, out := network.addNode(), network.addNode()
+				network.addEdge(source, in, 0, fragments)
+				network.addEdge(in, out, 0, limit(first))
+				if fromCollector >= 0 {
+					network.addEdge(fromCollector, in, 0, 1)
+				}
+				froms[value] = out
+			}
+			from = froms[value]
+		}
+
+		to := sink
+		if second != "" {
+			value := dc.Labels[second]
+			if _, ok := tos[value]; !ok {
+				in, out := network.addNode(), network.addNode()
+				network.addEdge(in, out, 0, limit(second))
+				network.addEdge(out, sink, 0, fragments)
+				if toCollector >= 0 {
+					network.addEdge(out, toCollector, 0, 1)
+				}
+				tos[value] = in
+			}
+			to = tos[value]
+		}
+
+		node := network.addNode()
+		edges[i] = network.addEdge(from, node, 0, caps[i])
+		network.addEdge(node, to, 0, fragments)
+	}
+
+	if !network.feasible() {
+		return nil, false
+	}
+
+	counts := make([]int, len(dataCenters))
+	for i, e := range edges {
+		if e >= 0 {
+			counts[i] = network.flow(e)
+		}
+	}
+
+	return counts, true
+}
+
+// placeSearch is place with the constraints on any number of labels. The counts of the data centers
+// are tried one data center after the other from the highest, a branch is cut once the capacity left
+// is below the fragments left or a MinDistinct label has too few values left to reach its minimum.
+func placeSearch(dataCenters []DataCenter, caps []int, fragments int, list []constraint) ([]int, bool) {
+	var candidates []int
+	for i, c := range caps {
+		if c > 0 {
+			candidates = append(candidates, i)
+		}
+	}
+	// rest[j] is the capacity of the candidates from j on
+	rest := make([]int, len(candidates)+1)
+	for j := len(candidates) - 1; j >= 0; j-- {
+		rest[j] = rest[j+1] + caps[candidates[j]]
+	}
+
+	used := make(map[string]map[string]int) // label -> value -> fragments
+	for _, c := range list {
+		used[c.label] = make(map[string]int)
+	}
+	holding := make(map[string]int) // label -> values holding fragments
+	counts := make([]int, len(dataCenters))
+
+	// distinctLeft reports whether the MinDistinct constraints can still be reached
+	// with the candidates from j on and the fragments left
+	distinctLeft := func(j, remaining int) bool {
+		for _, c := range list {
+			missing := c.limit - holding[c.label]
+			if !c.minDistinct || missing <= 0 {
+				continue
+			}
+			if missing > remaining {
+				return false
+			}
+
+			values := make(map[string]bool)
+			for _, i := range candidates[j:] {
+				if value := dataCenters[i].Labels[c.label]; used[c.label][value] == 0 {
+					values[value] = true
+				}
+			}
+			if len(values) < missing {
+				return false
+			}
+		}
+		return true
+	}
+
+	var search func(j, remaining int) bool
+	search = func(j, remaining int) bool {
+		if remaining == 0 {
+			return distinctLeft(j, 0)
+		}
+		if rest[j] < remaining || !distinctLeft(j, remaining) {
+			return false
+		}
+
+		i := candidates[j]
+		most := min(caps[i], remaining)
+		for _, c := range list {
+			if !c.minDistinct {
+				most = min(most, c.limit-used[c.label][dataCenters[i].Labels[c.label]])
+			}
+		}
+
+		for n := most; n >= 0; n-- {
+			store(dataCenters[i], n, used, holding)
+			counts[i] = n
+			if search(j+1, remaining-n) {
+				return true
+			}
+			store(dataCenters[i], -n, used, holding)
+		}
+		counts[i] = 0
+
+		return false
+	}
+
+	if !search(0, fragments) {
+		return nil, false
+	}
+
+	return counts, true
+}
+
+// store adds n fragments of the data center to the fragments per label value and the values holding them.
+func store(dc DataCenter, n int, used map[string]map[string]int, holding map[string]int) {
+	if n == 0 {
+		return
+	}
+
+	for label, values := range used {
+		value := dc.Labels[label]
+		before := values[value]
+		values[value] += n
+		switch {
+		case before == 0:
+			holding[label]++
+		case values[value] == 0:
+			holding[label]--
+		}
+	}
+}
+
+// minimizeRisk returns the counts of a placement with the lowest maximum risk.
+// There must be a placement with the most fragments of every data center.
+func minimizeRisk(dataCenters []DataCenter, most []int, fragments int, list []constraint) []int {
+	logs := make([]float64, len(dataCenters))
+	hi := 0.0
+	for i, dc := range dataCenters {
+		logs[i] = math.Log(float64(dc.Risk))
+		hi = max(hi, float64(most[i])*logs[i])
+	}
+	// every data center holds its most fragments at hi
+	hi *= 1 + searchMargin
+
+	capsAt := func(limit float64) []int {
+		caps := make([]int, len(dataCenters))
+		for i, l := range logs {
+			if l == 0 {
+				caps[i] = most[i]
+				continue
+			}
+			caps[i] = fits(l, limit, most[i])
+		}
+		return caps
+	}
+	if counts, ok := place(dataCenters, capsAt(0), fragments, list); ok {
+		return counts
+	}
+
+	lo := 0.0
+	for i := 0; i < searchIterations && lo < hi; i++ {
+		mid := lo + (hi-lo)/2
+		if mid == lo || mid == hi {
+			break
+		}
+
+		if _, ok := place(dataCenters, capsAt(mid), fragments, list); ok {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	// float64 rounding can put the answer a fragment off the limits, so the exact risks around them are tried
+	var limits []Risk
+	for i, dc := range dataCenters {
+		if logs[i] == 0 || most[i] == 0 {
+			continue
+		}
+		from := max(fits(logs[i], lo*(1-searchMargin), most[i]), 1)
+		to := min(fits(logs[i], hi*(1+searchMargin), most[i])+1, most[i])
+		for n := from; n <= to; n++ {
+			limits = append(limits, Risk{dc.Risk, n})
+		}
+	}
+	slices.SortFunc(limits, Risk.Cmp)
+
+	for _, limit := range limits {
+		caps := make([]int, len(dataCenters))
+		for i, dc := range dataCenters {
+			caps[i] = allowedFragments(dc.Risk, limit, 0, most[i])
+		}
+		if counts, ok := place(dataCenters, caps, fragments, list); ok {
+			return counts
+		}
+	}
+
+	counts, _ := place(dataCenters, capsAt(hi), fragments, list)
+
+	return counts
+}
+
+// infeasibleError returns the ConstraintError of a constraint in a minimal set of the constraints
+// without a placement: the constraints are dropped one by one while the rest still have none.
+func infeasibleError(dataCenters []DataCenter, most []int, fragments int, list []constraint) error {
+	kept := slices.Clone(list)
+	for i := 0; i < len(kept); {
+		rest := slices.Delete(slices.Clone(kept), i, i+1)
+		if _, ok := place(dataCenters, most, fragments, rest); !ok {
+			kept = rest
+			continue
+		}
+		i++
+	}
+
+	return &ConstraintError{Label: kept[0].label, Limit: kept[0].limit, MinDistinct: kept[0].minDistinct}
+}
@@ -0,0 +1,343 @@
+package allocation
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestDistributeWithConstraints(t *testing.T) {
+	dataCenters := []DataCenter{
+		{Risk: 2, Capacity: 10, Labels: map[string]string{"region": "eu", "provider": "aws"}},
+		{Risk: 3, Capacity: 10, Labels: map[string]string{"region": "eu", "provider": "gcp"}},
+		{Risk: 5, Capacity: 10, Labels: map[string]string{"region": "us", "provider": "aws"}},
+		{Risk: 7, Capacity: 10, Labels: map[string]string{"region": "asia", "provider": "gcp"}},
+	}
+
+	testCases := []struct {
+		desc          string
+		dataCenters   []DataCenter
+		fragments     int
+		constraints   Constraints
+		expCounts     []int // nil if more placements have the minimized maximum risk
+		expRisk       string
+		expBottleneck int
+		expErr        string
+	}{
+		{
+			desc:          "Success_NoConstraints",
+			dataCenters:   dataCenters,
+			fragments:     4,
+			expCounts:     []int{2, 1, 1, 0},
+			expRisk:       "5",
+			expBottleneck: 2,
+		},
+		{
+			desc:        "Success_MaxPerLabel",
+			dataCenters: dataCenters,
+			fragments:   4,
+			constraints: Constraints{MaxPerLabel: map[string]int{"region": 2}},
+			expRisk:     "7",
+		},
+		{
+			desc:          "Success_MaxPerLabel_MultipleLabels",
+			dataCenters:   dataCenters,
+			fragments:     4,
+			constraints:   Constraints{MaxPerLabel: map[string]int{"region": 2, "provider": 2}},
+			expCounts:     []int{1, 1, 1, 1},
+			expRisk:       "7",
+			expBottleneck: 3,
+		},
+		{
+			desc:        "Success_MinDistinct",
+			dataCenters: dataCenters,
+			fragments:   3,
+			constraints: Constraints{MinDistinct: map[string]int{"region": 3}},
+			expRisk:     "7",
+		},
+		{
+			desc:          "Success_MinDistinct_MoreFragments",
+			dataCenters:   dataCenters,
+			fragments:     4,
+			constraints:   Constraints{MinDistinct: map[string]int{"region": 2}},
+			expCounts:     []int{2, 1, 1, 0},
+			expRisk:       "5",
+			expBottleneck: 2,
+		},
+		{
+			desc:          "Success_MaxPerLabel_And_MinDistinct",
+			dataCenters:   dataCenters,
+			fragments:     4,
+			constraints:   Constraints{MaxPerLabel: map[string]int{"provider": 2}, MinDistinct: map[string]int{"region": 3}},
+			expCounts:     []int{1, 1, 1, 1},
+			expRisk:       "7",
+			expBottleneck: 3,
+		},
+		{
+			// seeding one label at a time takes both r1 data centers for the providers
+			desc: "Success_MinDistinct_TwoLabels",
+			dataCenters: []DataCenter{
+				{Risk: 2, Capacity: 10, Labels: map[string]string{"region": "r1", "provider": "p1"}},
+				{Risk: 3, Capacity: 10, Labels: map[string]string{"region": "r1", "provider": "p2"}},
+				{Risk: 10, Capacity: 10, Labels: map[string]string{"region": "r2", "provider": "p2"}},
+				{Risk: 100, Capacity: 10, Labels: map[string]string{"region": "r2", "provider": "p1"}},
+			},
+			fragments:     2,
+			constraints:   Constraints{MinDistinct: map[string]int{"region": 2, "provider": 2}},
+			expCounts:     []int{1, 0, 1, 0},
+			expRisk:       "10",
+			expBottleneck: 2,
+		},
+		{
+			// both data centers without the label share its empty value
+			desc: "MissingLabel_ShouldSucceed",
+			dataCenters: []DataCenter{
+				{Risk: 2, Capacity: 10},
+				{Risk: 3, Capacity: 10},
+				{Risk: 10, Capacity: 10, Labels: map[string]string{"region": "eu"}},
+			},
+			fragments:   3,
+			constraints: Constraints{MaxPerLabel: map[string]int{"region": 2}},
+			expRisk:     "10",
+		},
+		{
+			desc:        "MaxPerLabel_Infeasible_ShouldFail",
+			dataCenters: dataCenters,
+			fragments:   4,
+			constraints: Constraints{MaxPerLabel: map[string]int{"region": 1}},
+			expErr:      `constraint cannot be satisfied: at most 1 fragments per "region"`,
+		},
+		{
+			desc:        "MinDistinct_TooFewValues_ShouldFail",
+			dataCenters: dataCenters,
+			fragments:   4,
+			constraints: Constraints{MinDistinct: map[string]int{"region": 4}},
+			expErr:      `constraint cannot be satisfied: at least 4 distinct "region"`,
+		},
+		{
+			// two providers cannot hold 4 fragments, whatever the regions are
+			desc:        "MaxPerLabel_InfeasibleAlone_ShouldFail",
+			dataCenters: dataCenters,
+			fragments:   4,
+			constraints: Constraints{MaxPerLabel: map[string]int{"provider": 1}, MinDistinct: map[string]int{"region": 3}},
+			expErr:      `constraint cannot be satisfied: at most 1 fragments per "provider"`,
+		},
+		{
+			// each limit alone is fine, but us and asia are both aws only
+			desc: "MaxPerLabel_InfeasibleTogether_ShouldFail",
+			dataCenters: []DataCenter{
+				{Risk: 2, Capacity: 10, Labels: map[string]string{"region": "eu", "provider": "aws"}},
+				{Risk: 3, Capacity: 10, Labels: map[string]string{"region": "eu", "provider": "gcp"}},
+				{Risk: 5, Capacity: 10, Labels: map[string]string{"region": "us", "provider": "aws"}},
+				{Risk: 7, Capacity: 10, Labels: map[string]string{"region": "asia", "provider": "aws"}},
+				{Risk: 11, Capacity: 10, Labels: map[string]string{"region": "eu", "provider": "azure"}},
+			},
+			fragments:   3,
+			constraints: Constraints{MaxPerLabel: map[string]int{"region": 1, "provider": 1}},
+			expErr:      `constraint cannot be satisfied: at most 1 fragments per "provider"`,
+		},
+		{
+			desc:        "MinDistinct_TooFewFragments_ShouldFail",
+			dataCenters: dataCenters,
+			fragments:   2,
+			constraints: Constraints{MinDistinct: map[string]int{"provider": 3}},
+			expErr:      `constraint cannot be satisfied: at least 3 distinct "provider"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			act, err := DistributeWithConstraints(tc.dataCenters, tc.fragments, tc.constraints)
+			if tc.expErr != "" {
+				th.AssertCorrectError(t, err, ErrInfeasibleConstraint)
+
+				var constraintErr *ConstraintError
+				if !errors.As(err, &constraintErr) {
+					t.Fatalf("exp: *ConstraintError, act: %T", err)
+				}
+				th.AssertEqualStrings(t, err.Error(), tc.expErr)
+				return
+			}
+			th.AssertNilError(t, err)
+			th.AssertEqualStrings(t, act.MaxRisk().String(), tc.expRisk)
+			assertConstraints(t, tc.dataCenters, act.Counts, tc.fragments, tc.constraints)
+			if tc.expCounts != nil {
+				th.AssertEqualIntSlices(t, act.Counts, tc.expCounts)
+				th.AssertEqualInts(t, act.Bottleneck, tc.expBottleneck)
+			}
+		})
+	}
+}
+
+func TestDistributeWithConstraints_MatchesExhaustiveSearch(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		labels []string
+	}{
+		{desc: "TwoLabels", labels: []string{"r", "p"}},
+		{desc: "ThreeLabels", labels: []string{"a", "b", "c"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+			for i := 0; i < 2000; i++ {
+				dataCenters := make([]DataCenter, 2+rnd.Intn(3))
+				for j := range dataCenters {
+					dataCenters[j] = DataCenter{Risk: 1 + rnd.Intn(12), Capacity: rnd.Intn(4), Labels: map[string]string{}}
+					for k, label := range tc.labels {
+						dataCenters[j].Labels[label] = fmt.Sprint(rnd.Intn(2 + k%2))
+					}
+				}
+				fragments := rnd.Intn(6)
+				constraints := Constraints{MaxPerLabel: map[string]int{}, MinDistinct: map[string]int{}}
+				for _, label := range tc.labels {
+					if rnd.Intn(2) == 0 {
+						constraints.MaxPerLabel[label] = 1 + rnd.Intn(3)
+					}
+					if rnd.Intn(2) == 0 {
+						constraints.MinDistinct[label] = 1 + rnd.Intn(2)
+					}
+				}
+
+				t.Run(fmt.Sprint(dataCenters, fragments, constraints), func(t *testing.T) {
+					assertExhaustive(t, dataCenters, fragments, constraints)
+				})
+			}
+		})
+	}
+}
+
+func TestDistributeWithConstraints_MoreLabels(t *testing.T) {
+	dataCenters := []DataCenter{
+		{Risk: 2, Capacity: 10, Labels: map[string]string{"region": "eu", "provider": "aws", "rack": "a"}},
+		{Risk: 3, Capacity: 10, Labels: map[string]string{"region": "eu", "provider": "gcp", "rack": "b"}},
+		{Risk: 5, Capacity: 10, Labels: map[string]string{"region": "us", "provider": "aws", "rack": "b"}},
+		{Risk: 7, Capacity: 10, Labels: map[string]string{"region": "asia", "provider": "gcp", "rack": "a"}},
+	}
+
+	constraints := Constraints{MaxPerLabel: map[string]int{"region": 2, "provider": 2, "rack": 2}}
+	act, err := DistributeWithConstraints(dataCenters, 4, constraints)
+	th.AssertNilError(t, err)
+	assertConstraints(t, dataCenters, act.Counts, 4, constraints)
+	th.AssertEqualStrings(t, act.MaxRisk().String(), "7")
+
+	// the region limit alone has no placement
+	constraints.MaxPerLabel["region"] = 1
+	_, err = DistributeWithConstraints(dataCenters, 4, constraints)
+	th.AssertEqualStrings(t, err.Error(), `constraint cannot be satisfied: at most 1 fragments per "region"`)
+
+	// 2 fragments in the first data center fill a0 and b0, the third one has nowhere to go
+	dataCenters = []DataCenter{
+		{Risk: 2, Capacity: 2, Labels: map[string]string{"a": "a0", "b": "b0", "c": "c1"}},
+		{Risk: 2, Capacity: 0},
+		{Risk: 12, Capacity: 3, Labels: map[string]string{"a": "a1", "b": "b0", "c": "c0"}},
+		{Risk: 12, Capacity: 3, Labels: map[string]string{"a": "a0", "b": "b1", "c": "c0"}},
+	}
+	constraints = Constraints{MaxPerLabel: map[string]int{"a": 2, "b": 2, "c": 3}}
+	act, err = DistributeWithConstraints(dataCenters, 3, constraints)
+	th.AssertNilError(t, err)
+	th.AssertEqualIntSlices(t, act.Counts, []int{1, 0, 1, 1})
+	th.AssertEqualStrings(t, act.MaxRisk().String(), "12")
+}
+
+func TestDistributeWithConstraints_InsufficientCapacity(t *testing.T) {
+	_, err := DistributeWithConstraints([]DataCenter{{Risk: 2, Capacity: 1}}, 2, Constraints{})
+	th.AssertCorrectError(t, err, ErrInsufficientCapacity)
+}
+
+func assertExhaustive(t *testing.T, dataCenters []DataCenter, fragments int, constraints Constraints) {
+	t.Helper()
+	exp, feasible := exhaustiveConstrainedRisk(dataCenters, fragments, constraints)
+	act, err := DistributeWithConstraints(dataCenters, fragments, constraints)
+	if !feasible {
+		th.AssertNotNilError(t, err)
+		if !errors.Is(err, ErrInfeasibleConstraint) && !errors.Is(err, ErrInsufficientCapacity) {
+			t.Errorf("exp: ErrInfeasibleConstraint or ErrInsufficientCapacity, act: %v", err)
+		}
+		return
+	}
+	th.AssertNilError(t, err)
+	assertConstraints(t, dataCenters, act.Counts, fragments, constraints)
+	th.AssertEqualInts(t, act.MaxRisk().Cmp(exp), 0)
+}
+
+// exhaustiveConstrainedRisk returns the lowest maximum risk of all placements honoring the constraints,
+// false if there is none.
+func exhaustiveConstrainedRisk(dataCenters []DataCenter, fragments int, constraints Constraints) (Risk, bool) {
+	var best Risk
+	found := false
+	counts := make([]int, len(dataCenters))
+
+	var try func(i, remaining int)
+	try = func(i, remaining int) {
+		if i == len(dataCenters) {
+			if remaining > 0 || !satisfies(dataCenters, counts, constraints) {
+				return
+			}
+			highest := Risk{dataCenters[0].Risk, counts[0]}
+			for j, dc := range dataCenters[1:] {
+				if r := (Risk{dc.Risk, counts[j+1]}); r.Cmp(highest) > 0 {
+					highest = r
+				}
+			}
+			if !found || highest.Cmp(best) < 0 {
+				best, found = highest, true
+			}
+			return
+		}
+
+		for n := 0; n <= min(dataCenters[i].Capacity, remaining); n++ {
+			counts[i] = n
+			try(i+1, remaining-n)
+		}
+		counts[i] = 0
+	}
+	try(0, fragments)
+
+	return best, found
+}
+
+// satisfies reports whether the counts honor the constraints.
+func satisfies(dataCenters []DataCenter, counts []int, constraints Constraints) bool {
+	for label, limit := range constraints.MaxPerLabel {
+		perValue := make(map[string]int)
+		for i, dc := range dataCenters {
+			perValue[dc.Labels[label]] += counts[i]
+		}
+		for _, count := range perValue {
+			if count > limit {
+				return false
+			}
+		}
+	}
+	for label, minimum := range constraints.MinDistinct {
+		values := make(map[string]bool)
+		for i, dc := range dataCenters {
+			if counts[i] > 0 {
+				values[dc.Labels[label]] = true
+			}
+		}
+		if len(values) < minimum {
+			return false
+		}
+	}
+
+	return true
+}
+
+func assertConstraints(t *testing.T, dataCenters []DataCenter, counts []int, fragments int, constraints Constraints) {
+	t.Helper()
+	stored := 0
+	for i, count := range counts {
+		if count > dataCenters[i].Capacity {
+			t.Errorf("data center %d holds %d fragments, capacity %d", i, count, dataCenters[i].Capacity)
+		}
+		stored += count
+	}
+	th.AssertEqualInts(t, stored, fragments)
+	if !satisfies(dataCenters, counts, constraints) {
+		t.Errorf("counts %v do not satisfy %v", counts, constraints)
+	}
+}
@@ -0,0 +1,139 @@
+package allocation
+
+import "math"
+
+// flowNetwork is a network of edges with lower and upper bounds on their flow.
+// feasible finds a circulation honoring all bounds, if there is one, with Dinic's max flow
+// on the network without the lower bounds: every lower bound is sent up front,
+// so its tail needs that much more flow in and its head that much more flow out.
+type flowNetwork struct {
+	heads  []int // edge -> node it leads to, the reverse edge of e is e^1
+	caps   []int // edge -> remaining capacity
+	lowers []int // edge -> lower bound, 0 for the reverse edges
+	first  [][]int
+	excess []int // node -> flow in minus flow out of the lower bounds
+	broken bool  // an edge has the upper bound below the lower one
+}
+
+func newFlowNetwork(nodes int) *flowNetwork {
+	return &flowNetwork{
+		first:  make([][]int, nodes),
+		excess: make([]int, nodes),
+	}
+}
+
+// addNode adds a node and returns its index.
+func (n *flowNetwork) addNode() int {
+	n.first = append(n.first, nil)
+	n.excess = append(n.excess, 0)
+
+	return len(n.first) - 1
+}
+
+// addEdge adds the edge from u to v with the flow between lower and upper, returns the edge index.
+func (n *flowNetwork) addEdge(u, v, lower, upper int) int {
+	e := len(n.heads)
+	n.heads = append(n.heads, v, u)
+	n.caps = append(n.caps, max(upper-lower, 0), 0)
+	n.lowers = append(n.lowers, lower, 0)
+	n.first[u] = append(n.first[u], e)
+	n.first[v] = append(n.first[v], e+1)
+	n.excess[v] += lower
+	n.excess[u] -= lower
+	n.broken = n.broken || upper < lower
+
+	return e
+}
+
+// flow returns the flow of the edge after feasible.
+func (n *flowNetwork) flow(e int) int {
+	return n.lowers[e] + n.caps[e^1]
+}
+
+// feasible reports whether there is a flow honoring all bounds in which the flow into every node
+// equals the flow out of it. The network keeps the flow, it can be read with flow.
+func (n *flowNetwork) feasible() bool {
+	if n.broken {
+		return false
+	}
+
+	source, sink := n.addNode(), n.addNode()
+	demand := 0
+	for v, excess := range n.excess[:source] {
+		switch {
+		case excess > 0:
+			n.addEdge(source, v, 0, excess)
+			demand += excess
+		case excess < 0:
+			n.addEdge(v, sink, 0, -excess)
+		}
+	}
+
+	return n.maxFlow(source, sink) == demand
+}
+
+// maxFlow sends the maximum flow from source to sink with Dinic's algorithm.
+func (n *flowNetwork) maxFlow(source, sink int) int {
+	total := 0
+	level := make([]int, len(n.first))
+	next := make([]int, len(n.first))
+	for n.levels(source, sink, level) {
+		clear(next)
+		for {
+			pushed := n.push(source, sink, math.MaxInt, level, next)
+			if pushed == 0 {
+				break
+			}
+			total += pushed
+		}
+	}
+
+	return total
+}
+
+// levels sets the BFS distance of the nodes from source over the edges with capacity left,
+// reports whether sink is reachable.
+func (n *flowNetwork) levels(source, sink int, level []int) bool {
+	for i := range level {
+		level[i] = -1
+	}
+	level[source] = 0
+
+	queue := []int{source}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, e := range n.first[u] {
+			if v := n.heads[e]; n.caps[e] > 0 && level[v] < 0 {
+				level[v] = level[u] + 1
+				queue = append(queue, v)
+			}
+		}
+	}
+
+	return level[sink] >= 0
+}
+
+// push sends at most limit flow from u to sink along the level graph, returns the flow sent.
+// next keeps the first edge of every node which may still have an augmenting path.
+func (n *flowNetwork) push(u, sink, limit int, level, next []int) int {
+	if u == sink {
+		return limit
+	}
+
+	for ; next[u] < len(n.first[u]); next[u]++ {
+		e := n.first[u][next[u]]
+		v := n.heads[e]
+		if n.caps[e] == 0 || level[v] != level[u]+1 {
+			continue
+		}
+
+		if pushed := n.push(v, sink, min(limit, n.caps[e]), level, next); pushed > 0 {
+			n.caps[e] -= pushed
+			n.caps[e^1] += pushed
+			return pushed
+		}
+	}
+
+	return 0
+}
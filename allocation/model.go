@@ -0,0 +1,280 @@
+package allocation
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+var (
+	ErrInvalidProbability = errors.New("breach probability must be between 0 and 1")
+	ErrInvalidCounts      = errors.New("counts must be non-negative, one per data center")
+)
+
+// RiskModel scores how risky it is to store fragments in the data centers.
+type RiskModel interface {
+	// Allocate distributes the fragments among the data centers, minimizing the risk.
+	// Returns the number of fragments in each data center.
+	Allocate(fragments int) ([]int, error)
+	// Risk returns the risk of the data centers holding the fragments, lower is better.
+	// Returns ErrInvalidCounts unless there is a non-negative count for every data center.
+	Risk(counts []int) (float64, error)
+}
+
+// ModelAllocation is the result of distributing fragments with a RiskModel.
+type ModelAllocation struct {
+	Counts []int   // number of fragments stored in each data center
+	Risk   float64 // risk of the distribution in the model
+}
+
+// DistributeWithModel distributes the fragments among the data centers of the model.
+//
+// Parameters:
+//   - model: the risk model of the data centers.
+//   - fragments: the number of fragments to distribute.
+//
+// Returns:
+//   - The ModelAllocation with the counts indexed like the data centers of the model.
+//   - An error of the model.
+func DistributeWithModel(model RiskModel, fragments int) (ModelAllocation, error) {
+	counts, err := model.Allocate(fragments)
+	if err != nil {
+		return ModelAllocation{}, err
+	}
+
+	risk, err := model.Risk(counts)
+	if err != nil {
+		return ModelAllocation{}, err
+	}
+
+	return ModelAllocation{Counts: counts, Risk: risk}, nil
+}
+
+// ExponentialModel is the risk model of Distribute: a data center with base risk b holding n fragments
+// has risk b^n and the risk of the distribution is the maximum of them.
+type ExponentialModel struct {
+	Risks []int // base risk of each data center
+}
+
+// Allocate distributes the fragments with Distribute.
+func (m ExponentialModel) Allocate(fragments int) ([]int, error) {
+	allocation, err := Distribute(m.Risks, fragments)
+	if err != nil {
+		return nil, err
+	}
+
+	return allocation.Counts, nil
+}
+
+// Risk returns the maximum risk of the data centers, +Inf if it does not fit in float64.
+// Like Distribute, it returns ErrEmptyDataCenters without data centers and ErrNonPositiveRisk
+// for a base risk below 1.
+func (m ExponentialModel) Risk(counts []int) (float64, error) {
+	if err := validate(m.Risks, 0); err != nil {
+		return 0, err
+	}
+	if err := validateCounts(counts, len(m.Risks)); err != nil {
+		return 0, err
+	}
+
+	highest := Risk{m.Risks[0], counts[0]}
+	for i, risk := range m.Risks[1:] {
+		if r := (Risk{risk, counts[i+1]}); r.Cmp(highest) > 0 {
+			highest = r
+		}
+	}
+
+	if v, ok := highest.value(); ok {
+		return float64(v), nil
+	}
+
+	return math.Exp(highest.Log()), nil
+}
+
+// ProbabilityModel is the risk model of independent breaches: each data center is breached
+// with its probability, the attacker gets all fragments of the breached data centers
+// and can reconstruct the data with Threshold of them.
+// The risk of the distribution is the probability the attacker gets at least Threshold fragments.
+type ProbabilityModel struct {
+	Probabilities []float64 // breach probability of each data center
+	Threshold     int       // number of fragments needed for the reconstruction
+}
+
+// Risk returns the probability the breached data centers hold at least Threshold fragments.
+// It sums the probabilities of the numbers of breached fragments data center by data center,
+// O(n*Threshold).
+func (m ProbabilityModel) Risk(counts []int) (float64, error) {
+	if err := m.validate(); err != nil {
+		return 0, err
+	}
+	if err := validateCounts(counts, len(m.Probabilities)); err != nil {
+		return 0, err
+	}
+
+	return m.risk(counts), nil
+}
+
+// validate checks the probabilities are between 0 and 1.
+func (m ProbabilityModel) validate() error {
+	for i, p := range m.Probabilities {
+		if !(p >= 0 && p <= 1) {
+			return fmt.Errorf("%w: %v of data center %d", ErrInvalidProbability, p, i)
+		}
+	}
+
+	return nil
+}
+
+// risk is Risk of valid counts.
+func (m ProbabilityModel) risk(counts []int) float64 {
+	if m.Threshold <= 0 {
+		return 1
+	}
+
+	breached := make([]float64, m.Threshold+1)
+	breached[0] = 1
+	for i, p := range m.Probabilities {
+		breached = m.breach(breached, counts[i], p)
+	}
+
+	return breached[m.Threshold]
+}
+
+// breach returns the probabilities of the numbers of breached fragments once a data center
+// with the count and the probability p is added. breached[j] is the probability of j breached fragments,
+// Threshold stands for Threshold or more. Needs a positive Threshold.
+func (m ProbabilityModel) breach(breached []float64, count int, p float64) []float64 {
+	next := make([]float64, m.Threshold+1)
+	for j, probability := range breached {
+		next[min(j+count, m.Threshold)] += probability * p
+		next[j] += probability * (1 - p)
+	}
+
+	return next
+}
+
+// Allocate distributes the fragments, minimizing the probability of the reconstruction.
+// The probability depends on all data centers together, so the distributions are searched
+// with branch and bound. A data center holding Threshold fragments is lost with its breach
+// whatever it holds on top, so only the counts 0..Threshold are tried and the fragments left over
+// go to a data center which already holds Threshold. The data centers are tried from the safest one
+// with the highest counts first, starting from all fragments in the safest data center,
+// and a branch is cut once its lower bound reaches the best probability. The worst case is
+// O((Threshold+1)^n * Threshold), the bound keeps tens of data centers fast in practice.
+func (m ProbabilityModel) Allocate(fragments int) ([]int, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	n := len(m.Probabilities)
+	if n == 0 {
+		return nil, ErrEmptyDataCenters
+	}
+	if fragments < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrNegativeFragments, fragments)
+	}
+
+	// data centers from the safest one
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(m.Probabilities[a], m.Probabilities[b]) })
+
+	best := make([]int, n)
+	best[order[0]] = fragments
+	if m.Threshold <= 0 {
+		return best, nil
+	}
+
+	s := &probabilitySearch{
+		model:  m,
+		order:  order,
+		counts: make([]int, n),
+		best:   best,
+		lowest: m.risk(best),
+	}
+	breached := make([]float64, m.Threshold+1)
+	breached[0] = 1
+	s.search(0, fragments, breached, -1)
+
+	return s.best, nil
+}
+
+// probabilitySearch keeps the state of the branch and bound of ProbabilityModel.Allocate.
+type probabilitySearch struct {
+	model  ProbabilityModel
+	order  []int // data centers from the safest one
+	counts []int // counts of the data centers chosen so far, capped at Threshold
+	best   []int
+	lowest float64 // risk of best
+}
+
+// search tries the counts of the data center order[i] and the ones after it with the fragments left.
+// breached holds the probabilities of the numbers of breached fragments in the data centers before it,
+// full is a data center before it holding Threshold fragments, -1 if none.
+func (s *probabilitySearch) search(i, fragments int, breached []float64, full int) {
+	threshold := s.model.Threshold
+	if s.bound(i, fragments, breached, full) >= s.lowest {
+		return
+	}
+
+	if i == len(s.order) {
+		if fragments > 0 && full < 0 {
+			return
+		}
+
+		s.lowest = breached[threshold]
+		copy(s.best, s.counts)
+		if fragments > 0 {
+			s.best[full] += fragments
+		}
+		return
+	}
+
+	dc := s.order[i]
+	for count := min(fragments, threshold); count >= 0; count-- {
+		s.counts[dc] = count
+		next := full
+		if count == threshold && full < 0 {
+			next = dc
+		}
+		s.search(i+1, fragments-count, s.model.breach(breached, count, s.model.Probabilities[dc]), next)
+	}
+	s.counts[dc] = 0
+}
+
+// bound returns a lower bound of the risk of the branch. Unless a data center holding Threshold
+// takes them, the fragments left go to the data centers from order[i] on, one of which gets
+// at least their share and is breached at least as likely as order[i].
+func (s *probabilitySearch) bound(i, fragments int, breached []float64, full int) float64 {
+	threshold := s.model.Threshold
+	risk := breached[threshold]
+	if full >= 0 || fragments == 0 || i == len(s.order) {
+		return risk
+	}
+
+	share := (fragments + len(s.order) - i - 1) / (len(s.order) - i)
+	p := s.model.Probabilities[s.order[i]]
+	for j := max(threshold-share, 0); j < threshold; j++ {
+		risk += breached[j] * p
+	}
+
+	return risk
+}
+
+// validateCounts checks there is a non-negative count for each of the n data centers.
+func validateCounts(counts []int, n int) error {
+	if len(counts) != n {
+		return fmt.Errorf("%w: %d counts, %d data centers", ErrInvalidCounts, len(counts), n)
+	}
+	for i, count := range counts {
+		if count < 0 {
+			return fmt.Errorf("%w: %d in data center %d", ErrInvalidCounts, count, i)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package allocation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestDistributeWithModel_Exponential(t *testing.T) {
+	act, err := DistributeWithModel(ExponentialModel{Risks: []int{10, 20, 30}}, 5)
+	th.AssertNilError(t, err)
+	th.AssertEqualIntSlices(t, act.Counts, []int{2, 2, 1})
+	assertEqualProbabilities(t, act.Risk, 400)
+
+	risk, err := ExponentialModel{Risks: []int{30}}.Risk([]int{300})
+	th.AssertNilError(t, err)
+	if !math.IsInf(risk, 1) {
+		t.Errorf("exp: +Inf, act: %v", risk)
+	}
+	_, err = ExponentialModel{}.Risk([]int{})
+	th.AssertCorrectError(t, err, ErrEmptyDataCenters)
+
+	_, err = DistributeWithModel(ExponentialModel{Risks: []int{10, -1}}, 5)
+	th.AssertCorrectError(t, err, ErrNonPositiveRisk)
+}
+
+func TestDistributeWithModel_Probability(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		model     ProbabilityModel
+		fragments int
+		expCounts []int
+		expRisk   float64
+		expErr    error
+	}{
+		{
+			// 2 of 3 breached: 0.1*0.2 + 0.1*0.3 + 0.2*0.3 - 2*0.1*0.2*0.3
+			desc:      "Success_Spread",
+			model:     ProbabilityModel{Probabilities: []float64{0.1, 0.2, 0.3}, Threshold: 2},
+			fragments: 3,
+			expCounts: []int{1, 1, 1},
+			expRisk:   0.098,
+		},
+		{
+			desc:      "Success_AllThreeNeeded",
+			model:     ProbabilityModel{Probabilities: []float64{0.5, 0.1, 0.5}, Threshold: 3},
+			fragments: 3,
+			expCounts: []int{1, 1, 1},
+			expRisk:   0.025,
+		},
+		{
+			// spreading to the risky data centers is worse than the safest one alone,
+			// [1 2 0] and [0 2 1] are as good
+			desc:      "Success_Concentrated",
+			model:     ProbabilityModel{Probabilities: []float64{0.9, 0.1, 0.9}, Threshold: 2},
+			fragments: 3,
+			expRisk:   0.1,
+		},
+		{
+			// moving single fragments from [0 3 3] (0.036) does not lower the probability
+			desc:      "Success_BeyondLocalMinimum",
+			model:     ProbabilityModel{Probabilities: []float64{0.39, 0.9, 0.04}, Threshold: 4},
+			fragments: 6,
+			expCounts: []int{3, 0, 3},
+			expRisk:   0.0156,
+		},
+		{
+			desc:      "FragmentsBelowThreshold_ShouldReturn_Zero",
+			model:     ProbabilityModel{Probabilities: []float64{0.9, 0.1}, Threshold: 3},
+			fragments: 2,
+			expCounts: []int{0, 2},
+			expRisk:   0,
+		},
+		{
+			desc:      "InvalidProbability_ShouldFail",
+			model:     ProbabilityModel{Probabilities: []float64{0.1, 1.5}, Threshold: 2},
+			fragments: 2,
+			expErr:    ErrInvalidProbability,
+		},
+		{
+			desc:      "NoDataCenters_ShouldFail",
+			model:     ProbabilityModel{Threshold: 2},
+			fragments: 2,
+			expErr:    ErrEmptyDataCenters,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			act, err := DistributeWithModel(tc.model, tc.fragments)
+			if tc.expErr != nil {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+			th.AssertNilError(t, err)
+			if tc.expCounts != nil {
+				th.AssertEqualIntSlices(t, act.Counts, tc.expCounts)
+			}
+			assertEqualProbabilities(t, act.Risk, tc.expRisk)
+		})
+	}
+}
+
+func TestModel_Risk_InvalidInput(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		model  RiskModel
+		counts []int
+		expErr error // ErrInvalidCounts if nil
+	}{
+		{
+			desc:   "Exponential_TooFewCounts_ShouldFail",
+			model:  ExponentialModel{Risks: []int{10, 20}},
+			counts: []int{1},
+		},
+		{
+			desc:   "Exponential_NegativeCount_ShouldFail",
+			model:  ExponentialModel{Risks: []int{10, 20}},
+			counts: []int{1, -1},
+		},
+		{
+			desc:   "Probability_TooManyCounts_ShouldFail",
+			model:  ProbabilityModel{Probabilities: []float64{0.1}, Threshold: 2},
+			counts: []int{1, 1},
+		},
+		{
+			desc:   "Probability_NegativeCount_ShouldFail",
+			model:  ProbabilityModel{Probabilities: []float64{0.1, 0.2}, Threshold: 2},
+			counts: []int{-1, 3},
+		},
+		{
+			desc:   "Exponential_NonPositiveRisk_ShouldFail",
+			model:  ExponentialModel{Risks: []int{10, 0}},
+			counts: []int{1, 1},
+			expErr: ErrNonPositiveRisk,
+		},
+		{
+			desc:   "Probability_InvalidProbability_ShouldFail",
+			model:  ProbabilityModel{Probabilities: []float64{0.1, 1.5}, Threshold: 2},
+			counts: []int{1, 1},
+			expErr: ErrInvalidProbability,
+		},
+		{
+			desc:   "Probability_NaNProbability_ShouldFail",
+			model:  ProbabilityModel{Probabilities: []float64{math.NaN()}, Threshold: 2},
+			counts: []int{1},
+			expErr: ErrInvalidProbability,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := tc.model.Risk(tc.counts)
+			if tc.expErr == nil {
+				tc.expErr = ErrInvalidCounts
+			}
+			th.AssertCorrectError(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestProbabilityModel_MatchesExhaustiveSearch(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		model := ProbabilityModel{Probabilities: make([]float64, 1+rnd.Intn(4)), Threshold: rnd.Intn(6)}
+		for j := range model.Probabilities {
+			model.Probabilities[j] = rnd.Float64()
+		}
+		fragments := rnd.Intn(10)
+
+		t.Run(fmt.Sprint(model, fragments), func(t *testing.T) {
+			act, err := DistributeWithModel(model, fragments)
+			th.AssertNilError(t, err)
+
+			stored := 0
+			for _, count := range act.Counts {
+				stored += count
+			}
+			th.AssertEqualInts(t, stored, fragments)
+			assertEqualProbabilities(t, act.Risk, exhaustiveRisk(model, make([]int, len(model.Probabilities)), 0, fragments))
+		})
+	}
+}
+
+// exhaustiveRisk returns the lowest risk of all distributions of the fragments
+// among the data centers from i on.
+func exhaustiveRisk(model ProbabilityModel, counts []int, i, fragments int) float64 {
+	if i == len(counts)-1 {
+		counts[i] = fragments
+		return model.risk(counts)
+	}
+
+	lowest := 1.0
+	for n := 0; n <= fragments; n++ {
+		counts[i] = n
+		lowest = min(lowest, exhaustiveRisk(model, counts, i+1, fragments-n))
+	}
+
+	return lowest
+}
+
+func assertEqualProbabilities(t *testing.T, act, exp float64) {
+	t.Helper()
+	if math.Abs(act-exp) > 1e-9 {
+		t.Errorf("exp: %v, act: %v", exp, act)
+	}
+}
@@ -0,0 +1,170 @@
+package allocation
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	ErrInvalidPlacement = errors.New("fragment is placed in an unknown data center")
+	ErrInvalidChange    = errors.New("change refers to an unknown data center")
+	ErrInvalidTolerance = errors.New("tolerance must be a non-negative finite number")
+)
+
+// ChangeKind is the kind of a change of the data centers.
+type ChangeKind int
+
+const (
+	AddDataCenter    ChangeKind = iota // a new data center with Risk gets the next index
+	RemoveDataCenter                   // DataCenter keeps its index, but holds no fragments
+	ChangeRisk                         // DataCenter gets the base risk Risk
+)
+
+// Change is a change of the data centers.
+type Change struct {
+	Kind       ChangeKind
+	DataCenter int // index of the removed or changed data center
+	Risk       int // base risk of the added or changed data center
+}
+
+// Move is a fragment moved between data centers.
+type Move struct {
+	Fragment int
+	From     int
+	To       int
+}
+
+// Rebalancing is the result of rebalancing the fragments after a change of the data centers.
+// The data centers keep their indices, a removed data center holds no fragments.
+type Rebalancing struct {
+	Allocation
+	Placement []int // index of the data center holding each fragment
+	Moves     []Move
+}
+
+// Rebalance moves the fragments of an existing placement after a change of the data centers,
+// so the maximum risk is again the minimized one, or within the tolerance of it.
+//
+// The minimized maximum risk R of the changed data centers is found with DistributeWithCapacity.
+// A data center can keep floor(ln(R*(1+tolerance))/ln(baseRisk)) fragments, only the fragments
+// above that have to move and each of them moves once, which is the minimal number of moves.
+// They move to the data centers with room left the same way as in DistributeWithCapacity.
+//
+// Parameters:
+//   - risks: base risks of the data centers before the change.
+//   - placement: index of the data center holding each fragment.
+//   - change: the change of the data centers.
+//   - tolerance: how much the maximum risk can exceed the minimized one, 0.1 for 10%.
+//
+// Returns:
+//   - The Rebalancing, indexed like the data centers after the change.
+//   - ErrInvalidTolerance for a negative, NaN or infinite tolerance.
+//   - ErrInvalidPlacement, ErrInvalidChange or ErrInsufficientCapacity if all data centers are removed.
+func Rebalance(risks []int, placement []int, change Change, tolerance float64) (Rebalancing, error) {
+	if !(tolerance >= 0) || math.IsInf(tolerance, 1) {
+		return Rebalancing{}, fmt.Errorf("%w: %v", ErrInvalidTolerance, tolerance)
+	}
+
+	dataCenters := make([]DataCenter, len(risks), len(risks)+1)
+	for i, risk := range risks {
+		dataCenters[i] = DataCenter{Risk: risk, Capacity: len(placement)}
+	}
+
+	switch change.Kind {
+	case AddDataCenter:
+		dataCenters = append(dataCenters, DataCenter{Risk: change.Risk, Capacity: len(placement)})
+	case RemoveDataCenter, ChangeRisk:
+		if change.DataCenter < 0 || change.DataCenter >= len(risks) {
+			return Rebalancing{}, fmt.Errorf("%w: %d of %d", ErrInvalidChange, change.DataCenter, len(risks))
+		}
+		if change.Kind == RemoveDataCenter {
+			dataCenters[change.DataCenter].Capacity = 0
+		} else {
+			dataCenters[change.DataCenter].Risk = change.Risk
+		}
+	default:
+		return Rebalancing{}, fmt.Errorf("%w: kind %d", ErrInvalidChange, change.Kind)
+	}
+
+	rebalancing := Rebalancing{
+		Allocation: newAllocation(len(dataCenters)),
+		Placement:  make([]int, len(placement)),
+	}
+	held := make([][]int, len(dataCenters)) // fragments held by each data center
+	for fragment, dc := range placement {
+		if dc < 0 || dc >= len(risks) {
+			return Rebalancing{}, fmt.Errorf("%w: fragment %d in %d of %d", ErrInvalidPlacement, fragment, dc, len(risks))
+		}
+		held[dc] = append(held[dc], fragment)
+		rebalancing.Placement[fragment] = dc
+	}
+
+	optimal, err := DistributeWithCapacity(dataCenters, len(placement))
+	if err != nil {
+		return Rebalancing{}, err
+	}
+	limit := optimal.MaxRisk()
+
+	// the data centers with room left, the fragments above the limit have to move
+	var moving []int
+	receivers := make([]dataCenter, 0, len(dataCenters))
+	for i, dc := range dataCenters {
+		// the optimal counts always fit, even if float64 rounding of the tolerance says otherwise
+		allowed := max(allowedFragments(dc.Risk, limit, tolerance, dc.Capacity), optimal.Counts[i])
+		keep := min(len(held[i]), allowed)
+		moving = append(moving, held[i][keep:]...)
+		for _, fragment := range held[i][keep:] {
+			rebalancing.Moves = append(rebalancing.Moves, Move{Fragment: fragment, From: i})
+		}
+
+		rebalancing.Counts[i] = keep
+		rebalancing.Risks[i] = Risk{dc.Risk, keep}
+		if keep < allowed {
+			receivers = append(receivers, dataCenter{baseRisk: dc.Risk, fragments: keep, capacity: allowed, index: i})
+		}
+	}
+
+	dataCentersHeap := newMinHeap(receivers)
+	for i, fragment := range moving {
+		dc := dataCentersHeap.peekDataCenter()
+		dc.IncreaseRisk()
+		rebalancing.Moves[i].To = dc.index
+		rebalancing.Placement[fragment] = dc.index
+		rebalancing.Counts[dc.index] = dc.fragments
+		rebalancing.Risks[dc.index] = dc.risk()
+
+		if dc.full() {
+			heap.Pop(dataCentersHeap)
+			continue
+		}
+		heap.Fix(dataCentersHeap, 0)
+	}
+	rebalancing.findBottleneck()
+
+	return rebalancing, nil
+}
+
+// allowedFragments returns the number of fragments a data center can hold with the risk
+// at most limit*(1+tolerance), capped at most. Without tolerance the risks are compared exactly.
+func allowedFragments(baseRisk int, limit Risk, tolerance float64, most int) int {
+	if baseRisk <= 1 {
+		return most
+	}
+
+	n := fits(math.Log(float64(baseRisk)), limit.Log()+math.Log1p(tolerance), most)
+	if tolerance > 0 {
+		return n
+	}
+
+	// float64 rounding can put the count one off the exact one
+	for n < most && (Risk{baseRisk, n + 1}).Cmp(limit) <= 0 {
+		n++
+	}
+	for n > 0 && (Risk{baseRisk, n}).Cmp(limit) > 0 {
+		n--
+	}
+
+	return n
+}
@@ -0,0 +1,151 @@
+package allocation
+
+import (
+	"math"
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestRebalance(t *testing.T) {
+	// Distribute([]int{10, 20, 30}, 5) stores 2, 2 and 1 fragments
+	risks := []int{10, 20, 30}
+	placement := []int{0, 0, 1, 1, 2}
+
+	testCases := []struct {
+		desc         string
+		risks        []int
+		placement    []int
+		change       Change
+		tolerance    float64
+		expPlacement []int
+		expMoves     []Move
+		expRisk      int
+		expErr       error
+	}{
+		{
+			// 5, 10, 20, 5^2, 30
+			desc:         "AddDataCenter",
+			risks:        risks,
+			placement:    placement,
+			change:       Change{Kind: AddDataCenter, Risk: 5},
+			expPlacement: []int{0, 3, 1, 3, 2},
+			expMoves:     []Move{{Fragment: 1, From: 0, To: 3}, {Fragment: 3, From: 1, To: 3}},
+			expRisk:      30,
+		},
+		{
+			// up to 330, 10^2 can stay
+			desc:         "AddDataCenter_WithTolerance",
+			risks:        risks,
+			placement:    placement,
+			change:       Change{Kind: AddDataCenter, Risk: 5},
+			tolerance:    10,
+			expPlacement: []int{0, 0, 1, 3, 2},
+			expMoves:     []Move{{Fragment: 3, From: 1, To: 3}},
+			expRisk:      100,
+		},
+		{
+			// 20^3 and 30^2
+			desc:         "RemoveDataCenter",
+			risks:        risks,
+			placement:    placement,
+			change:       Change{Kind: RemoveDataCenter, DataCenter: 0},
+			expPlacement: []int{2, 1, 1, 1, 2},
+			expMoves:     []Move{{Fragment: 0, From: 0, To: 2}, {Fragment: 1, From: 0, To: 1}},
+			expRisk:      8000,
+		},
+		{
+			// 2, 4, 8, 10, 16
+			desc:         "ChangeRisk",
+			risks:        risks,
+			placement:    placement,
+			change:       Change{Kind: ChangeRisk, DataCenter: 2, Risk: 2},
+			expPlacement: []int{0, 2, 2, 2, 2},
+			expMoves:     []Move{{Fragment: 1, From: 0, To: 2}, {Fragment: 2, From: 1, To: 2}, {Fragment: 3, From: 1, To: 2}},
+			expRisk:      16,
+		},
+		{
+			desc:         "ChangeRisk_StillOptimal_ShouldNotMove",
+			risks:        risks,
+			placement:    placement,
+			change:       Change{Kind: ChangeRisk, DataCenter: 1, Risk: 21},
+			expPlacement: placement,
+			expMoves:     []Move{},
+			expRisk:      441,
+		},
+		{
+			desc:      "InvalidPlacement_ShouldFail",
+			risks:     risks,
+			placement: []int{0, 3},
+			change:    Change{Kind: AddDataCenter, Risk: 5},
+			expErr:    ErrInvalidPlacement,
+		},
+		{
+			desc:      "InvalidChange_ShouldFail",
+			risks:     risks,
+			placement: placement,
+			change:    Change{Kind: RemoveDataCenter, DataCenter: 3},
+			expErr:    ErrInvalidChange,
+		},
+		{
+			desc:      "ChangeRisk_ToZero_ShouldFail",
+			risks:     risks,
+			placement: placement,
+			change:    Change{Kind: ChangeRisk, DataCenter: 1, Risk: 0},
+			expErr:    ErrNonPositiveRisk,
+		},
+		{
+			desc:      "RemoveLastDataCenter_ShouldFail",
+			risks:     []int{10},
+			placement: []int{0, 0},
+			change:    Change{Kind: RemoveDataCenter, DataCenter: 0},
+			expErr:    ErrInsufficientCapacity,
+		},
+		{
+			desc:      "NegativeTolerance_ShouldFail",
+			risks:     risks,
+			placement: placement,
+			change:    Change{Kind: AddDataCenter, Risk: 5},
+			tolerance: -0.1,
+			expErr:    ErrInvalidTolerance,
+		},
+		{
+			desc:      "NaNTolerance_ShouldFail",
+			risks:     risks,
+			placement: placement,
+			change:    Change{Kind: AddDataCenter, Risk: 5},
+			tolerance: math.NaN(),
+			expErr:    ErrInvalidTolerance,
+		},
+		{
+			desc:      "InfiniteTolerance_ShouldFail",
+			risks:     risks,
+			placement: placement,
+			change:    Change{Kind: AddDataCenter, Risk: 5},
+			tolerance: math.Inf(1),
+			expErr:    ErrInvalidTolerance,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			act, err := Rebalance(tc.risks, tc.placement, tc.change, tc.tolerance)
+			if tc.expErr != nil {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+			th.AssertNilError(t, err)
+			th.AssertEqualIntSlices(t, act.Placement, tc.expPlacement)
+
+			th.AssertEqualInts(t, len(act.Moves), len(tc.expMoves))
+			for i := range tc.expMoves {
+				if act.Moves[i] != tc.expMoves[i] {
+					t.Errorf("exp: %v, act: %v", tc.expMoves, act.Moves)
+				}
+			}
+
+			risk, err := act.MaxRisk().Int()
+			th.AssertNilError(t, err)
+			th.AssertEqualInts(t, risk, tc.expRisk)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package allocation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	ErrTooFewDataCenters = errors.New("fewer data centers than replicas")
+	ErrInvalidReplicas   = errors.New("number of replicas must be positive")
+	ErrTooManyReplicas   = errors.New("number of replicas overflows int")
+)
+
+// ReplicatedAllocation is the result of distributing replicated fragments among data centers.
+// Counts and Risks count every replica.
+type ReplicatedAllocation struct {
+	Allocation
+	Placement [][]int // indices of the data centers holding the replicas of each fragment
+}
+
+// DistributeReplicas distributes every fragment with the given number of replicas among data centers
+// represented by their risk values, never storing two replicas of the same fragment in one data center.
+// It minimizes the maximum risk the same way as Distribute.
+//
+// A data center can hold at most one replica of each fragment, so the replicas are distributed
+// by DistributeWithCapacity with the capacity of fragments for every data center. Any counts within
+// that capacity can be placed: the replicas are dealt to the fragments in turns, data center after
+// data center, and a data center gets at most one turn of every fragment.
+//
+// Parameters:
+//   - risks: a slice of integers representing the initial risk values of each data center.
+//   - fragments: the number of fragments to distribute.
+//   - replicas: the number of replicas of every fragment.
+//
+// Returns:
+//   - The ReplicatedAllocation, indexed like risks, with the placement of each fragment by its id (0 to fragments-1).
+//   - ErrEmptyDataCenters, ErrNonPositiveRisk or ErrNegativeFragments for invalid input.
+//   - ErrInvalidReplicas if replicas is below 1.
+//   - ErrTooFewDataCenters if there are fewer data centers than replicas.
+//   - ErrTooManyReplicas if fragments*replicas does not fit in int.
+func DistributeReplicas(risks []int, fragments, replicas int) (ReplicatedAllocation, error) {
+	if replicas < 1 {
+		return ReplicatedAllocation{}, fmt.Errorf("%w: %d", ErrInvalidReplicas, replicas)
+	}
+	if err := validate(risks, fragments); err != nil {
+		return ReplicatedAllocation{}, err
+	}
+	if len(risks) < replicas {
+		return ReplicatedAllocation{}, fmt.Errorf("%w: %d data centers, %d replicas", ErrTooFewDataCenters, len(risks), replicas)
+	}
+	if fragments > math.MaxInt/replicas {
+		return ReplicatedAllocation{}, fmt.Errorf("%w: %d fragments, %d replicas", ErrTooManyReplicas, fragments, replicas)
+	}
+
+	dataCenters := make([]DataCenter, len(risks))
+	for i, risk := range risks {
+		dataCenters[i] = DataCenter{Risk: risk, Capacity: fragments}
+	}
+
+	allocation, err := DistributeWithCapacity(dataCenters, fragments*replicas)
+	if err != nil {
+		return ReplicatedAllocation{}, err
+	}
+
+	placement := make([][]int, fragments)
+	for i := range placement {
+		placement[i] = make([]int, 0, replicas)
+	}
+
+	fragment := 0
+	for i, count := range allocation.Counts {
+		for range count {
+			placement[fragment] = append(placement[fragment], i)
+			fragment = (fragment + 1) % fragments
+		}
+	}
+
+	return ReplicatedAllocation{Allocation: allocation, Placement: placement}, nil
+}
@@ -0,0 +1,150 @@
+package allocation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestDistributeReplicas(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		risks        []int
+		fragments    int
+		replicas     int
+		expCounts    []int
+		expRisk      int
+		expPlacement [][]int
+		expErr       error
+	}{
+		{
+			desc:         "Success",
+			risks:        []int{10, 20, 30},
+			fragments:    2,
+			replicas:     2,
+			expCounts:    []int{2, 1, 1},
+			expRisk:      100,
+			expPlacement: [][]int{{0, 1}, {0, 2}},
+		},
+		{
+			// without the constraint all 4 replicas would go to the data center with risk 2
+			desc:         "Success_DistinctDataCenters",
+			risks:        []int{2, 100, 100},
+			fragments:    2,
+			replicas:     2,
+			expCounts:    []int{2, 1, 1},
+			expRisk:      100,
+			expPlacement: [][]int{{0, 1}, {0, 2}},
+		},
+		{
+			desc:         "ReplicasEqualToDataCenters_ShouldSucceed",
+			risks:        []int{3, 5},
+			fragments:    3,
+			replicas:     2,
+			expCounts:    []int{3, 3},
+			expRisk:      125,
+			expPlacement: [][]int{{0, 1}, {0, 1}, {0, 1}},
+		},
+		{
+			desc:         "SingleReplica_MatchesDistribute",
+			risks:        []int{10, 20, 30},
+			fragments:    5,
+			replicas:     1,
+			expCounts:    []int{2, 2, 1},
+			expRisk:      400,
+			expPlacement: [][]int{{0}, {0}, {1}, {1}, {2}},
+		},
+		{
+			desc:         "ZeroFragments_ShouldSucceed",
+			risks:        []int{10, 20},
+			fragments:    0,
+			replicas:     2,
+			expCounts:    []int{0, 0},
+			expRisk:      1,
+			expPlacement: [][]int{},
+		},
+		{
+			desc:      "TooFewDataCenters_ShouldFail",
+			risks:     []int{10, 20},
+			fragments: 2,
+			replicas:  3,
+			expErr:    ErrTooFewDataCenters,
+		},
+		{
+			desc:      "ZeroReplicas_ShouldFail",
+			risks:     []int{10, 20},
+			fragments: 2,
+			replicas:  0,
+			expErr:    ErrInvalidReplicas,
+		},
+		{
+			desc:      "NegativeReplicas_ShouldFail",
+			risks:     []int{10, 20},
+			fragments: 3,
+			replicas:  -1,
+			expErr:    ErrInvalidReplicas,
+		},
+		{
+			desc:      "ReplicasOverflow_ShouldFail",
+			risks:     []int{10, 20},
+			fragments: math.MaxInt/2 + 1,
+			replicas:  2,
+			expErr:    ErrTooManyReplicas,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			act, err := DistributeReplicas(tc.risks, tc.fragments, tc.replicas)
+			if tc.expErr != nil {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+			th.AssertNilError(t, err)
+			th.AssertEqualIntSlices(t, act.Counts, tc.expCounts)
+
+			risk, err := act.MaxRisk().Int()
+			th.AssertNilError(t, err)
+			th.AssertEqualInts(t, risk, tc.expRisk)
+
+			th.AssertEqualInts(t, len(act.Placement), len(tc.expPlacement))
+			for i := range tc.expPlacement {
+				th.AssertEqualIntSlices(t, act.Placement[i], tc.expPlacement[i])
+			}
+		})
+	}
+}
+
+func TestDistributeReplicas_DistinctDataCenters(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		risks := make([]int, 1+rnd.Intn(8))
+		for j := range risks {
+			risks[j] = 2 + rnd.Intn(50)
+		}
+		fragments := rnd.Intn(50)
+		replicas := 1 + rnd.Intn(len(risks))
+
+		t.Run(fmt.Sprint(risks, fragments, replicas), func(t *testing.T) {
+			act, err := DistributeReplicas(risks, fragments, replicas)
+			th.AssertNilError(t, err)
+
+			counts := make([]int, len(risks))
+			for _, centers := range act.Placement {
+				th.AssertEqualInts(t, len(centers), replicas)
+
+				seen := make(map[int]bool, len(centers))
+				for _, center := range centers {
+					if seen[center] {
+						t.Errorf("two replicas in data center %d: %v", center, centers)
+					}
+					seen[center] = true
+					counts[center]++
+				}
+			}
+			th.AssertEqualIntSlices(t, counts, act.Counts)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package allocation
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+var ErrRiskOverflow = errors.New("risk does not fit in int")
+
+// logTolerance is the relative difference of two logarithms below which
+// the float64 comparison is not trusted and the risks are compared exactly.
+const logTolerance = 1e-13
+
+// Risk is the exact risk of a data center in exponent form, Base^Exponent.
+// The value is never computed unless asked for, so it cannot overflow
+// no matter how many fragments are stored.
+type Risk struct {
+	Base     int
+	Exponent int
+}
+
+// Int returns the risk as int or ErrRiskOverflow if the value does not fit.
+func (r Risk) Int() (int, error) {
+	v, ok := r.value()
+	if !ok {
+		return 0, fmt.Errorf("%w: %d^%d", ErrRiskOverflow, r.Base, r.Exponent)
+	}
+
+	return v, nil
+}
+
+// value returns the risk as int, false if the value does not fit.
+// Unlike Int it does not allocate, the heap compares the risks with it.
+func (r Risk) value() (int, bool) {
+	abs := uint64(r.Base)
+	if r.Base < 0 {
+		abs = uint64(-r.Base)
+	}
+	negative := r.Base < 0 && r.Exponent%2 == 1
+
+	// exponentiation by squaring, the square is only taken when it is needed
+	result := uint64(1)
+	for e := r.Exponent; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			hi, lo := bits.Mul64(result, abs)
+			if hi != 0 || lo > math.MaxInt {
+				return 0, false
+			}
+			result = lo
+		}
+		if e > 1 {
+			hi, lo := bits.Mul64(abs, abs)
+			if hi != 0 || lo > math.MaxInt {
+				// the square is needed by a later bit, which makes the result even bigger
+				return 0, false
+			}
+			abs = lo
+		}
+	}
+
+	if negative {
+		return -int(result), true
+	}
+
+	return int(result), true
+}
+
+// BigInt returns the exact value of the risk.
+// The value has about Exponent*log2(Base) bits, which can be very large.
+func (r Risk) BigInt() *big.Int {
+	return new(big.Int).Exp(big.NewInt(int64(r.Base)), big.NewInt(int64(r.Exponent)), nil)
+}
+
+// Log returns the natural logarithm of the risk, Exponent*ln(Base).
+func (r Risk) Log() float64 {
+	if r.Exponent == 0 {
+		return 0
+	}
+
+	return float64(r.Exponent) * math.Log(float64(r.Base))
+}
+
+// Cmp compares the risks and returns -1, 0 or +1 like cmp.Compare.
+// Risks that fit in int are compared directly, the others in log domain.
+// When the logarithms are too close to be trusted the risks are compared exactly.
+func (r Risk) Cmp(other Risk) int {
+	if a, ok := r.value(); ok {
+		if b, ok := other.value(); ok {
+			return cmp.Compare(a, b)
+		}
+	}
+
+	a, b := r.Log(), other.Log()
+	if math.Abs(a-b) > logTolerance*max(math.Abs(a), math.Abs(b)) {
+		return cmp.Compare(a, b)
+	}
+
+	// The same root raised to the same power, e.g. 4^n and 2^2n, is a tie
+	// which can be decided without computing the values.
+	rootA, powerA := perfectPower(r.Base)
+	rootB, powerB := perfectPower(other.Base)
+	if rootA == rootB && rootA > 1 {
+		hiA, loA := bits.Mul64(uint64(powerA), uint64(r.Exponent))
+		hiB, loB := bits.Mul64(uint64(powerB), uint64(other.Exponent))
+		if c := cmp.Compare(hiA, hiB); c != 0 {
+			return c
+		}
+		return cmp.Compare(loA, loB)
+	}
+
+	return r.BigInt().Cmp(other.BigInt())
+}
+
+// String returns the value of the risk if it fits in int, Base^Exponent otherwise.
+func (r Risk) String() string {
+	if v, ok := r.value(); ok {
+		return fmt.Sprint(v)
+	}
+
+	return fmt.Sprintf("%d^%d", r.Base, r.Exponent)
+}
+
+// perfectPower returns the smallest root and the power with root^power = base.
+// Bases lower than 4 are their own root.
+func perfectPower(base int) (root, power int) {
+	root, power = base, 1
+	if base < 4 {
+		return root, power
+	}
+
+	for k := 2; k < bits.Len(uint(base)); k++ {
+		c := int(math.Round(math.Pow(float64(base), 1/float64(k))))
+		if v, ok := (Risk{c, k}).value(); ok && v == base {
+			root, power = c, k
+		}
+	}
+
+	return root, power
+}
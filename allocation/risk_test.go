@@ -0,0 +1,117 @@
+package allocation
+
+import (
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestRiskInt(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		risk   Risk
+		expOut int
+		expErr error
+	}{
+		{
+			desc:   "Success",
+			risk:   Risk{30, 3},
+			expOut: 27000,
+		},
+		{
+			desc:   "ZeroExponent_ShouldReturn_One",
+			risk:   Risk{30, 0},
+			expOut: 1,
+		},
+		{
+			desc:   "NegativeBase_ShouldSucceed",
+			risk:   Risk{-10, 3},
+			expOut: -1000,
+		},
+		{
+			desc:   "MaxInt64Range_ShouldSucceed",
+			risk:   Risk{2, 62},
+			expOut: 1 << 62,
+		},
+		{
+			desc:   "Overflow_ShouldFail",
+			risk:   Risk{2, 63},
+			expErr: ErrRiskOverflow,
+		},
+		{
+			// 30^13 is about 1.6e19, more than 9.2e18
+			desc:   "ModestInputs_Overflow_ShouldFail",
+			risk:   Risk{30, 13},
+			expErr: ErrRiskOverflow,
+		},
+		{
+			desc:   "BaseOne_HugeExponent_ShouldSucceed",
+			risk:   Risk{1, 1 << 40},
+			expOut: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			act, err := tc.risk.Int()
+			if tc.expErr != nil {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+			th.AssertNilError(t, err)
+			th.AssertEqualInts(t, act, tc.expOut)
+		})
+	}
+}
+
+func TestRiskCmp(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		a, b   Risk
+		expOut int
+	}{
+		{
+			desc:   "SmallRisks",
+			a:      Risk{10, 2},
+			b:      Risk{30, 1},
+			expOut: 1,
+		},
+		{
+			desc:   "HugeRisks_LogDomain",
+			a:      Risk{30, 1000},
+			b:      Risk{31, 990},
+			expOut: 1,
+		},
+		{
+			desc:   "HugeRisks_SameRoot_ShouldTie",
+			a:      Risk{4, 1 << 40},
+			b:      Risk{2, 1 << 41},
+			expOut: 0,
+		},
+		{
+			desc:   "HugeRisks_SameRoot",
+			a:      Risk{8, 1 << 40},
+			b:      Risk{4, 3<<39 + 1},
+			expOut: -1,
+		},
+		{
+			// 2^84 and 3^53 differ by about 0.2%
+			desc:   "CloseRisks_DifferentRoots",
+			a:      Risk{2, 84},
+			b:      Risk{3, 53},
+			expOut: -1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			th.AssertEqualInts(t, tc.a.Cmp(tc.b), tc.expOut)
+			th.AssertEqualInts(t, tc.b.Cmp(tc.a), -tc.expOut)
+		})
+	}
+}
+
+func TestRiskString(t *testing.T) {
+	th.AssertEqualStrings(t, Risk{20, 2}.String(), "400")
+	th.AssertEqualStrings(t, Risk{30, 13}.String(), "30^13")
+}
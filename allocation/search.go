@@ -0,0 +1,109 @@
+package allocation
+
+import (
+	"math"
+	"slices"
+)
+
+// searchMargin is the relative amount the lower bound of the binary search is lowered by,
+// so float64 rounding cannot put a risk above the answer into the initial counts.
+const searchMargin = 1e-12
+
+// searchIterations bisects the log range down to the float64 precision.
+const searchIterations = 100
+
+// DistributeBinarySearch distributes the fragments the same way as Distribute,
+// but instead of storing the fragments one by one it binary searches the maximum allowed risk R
+// in log space. A data center can hold floor(ln(R)/ln(baseRisk)) fragments below R,
+// so each step costs one pass over the data centers, independent of the number of fragments.
+// The float64 search stops just below the answer and the last few fragments are stored
+// with the heap, which keeps the result exact.
+//
+// Parameters:
+//   - risks: a slice of integers representing the initial risk values of each data center, at least 1.
+//   - fragments: the number of fragments to distribute.
+//
+// Returns:
+//   - The Allocation with the same maximum risk as Distribute.
+//     The counts can differ from Distribute between data centers with equal risks.
+//   - ErrEmptyDataCenters, ErrNonPositiveRisk or ErrNegativeFragments for invalid input.
+func DistributeBinarySearch(risks []int, fragments int) (Allocation, error) {
+	if err := validate(risks, fragments); err != nil {
+		return Allocation{}, err
+	}
+	if fragments == 0 || slices.Min(risks) == 1 {
+		return Distribute(risks, fragments)
+	}
+
+	logs := make([]float64, len(risks))
+	for i, risk := range risks {
+		logs[i] = math.Log(float64(risk))
+	}
+
+	// The capacity at lo stays below the fragments and the capacity at hi reaches them,
+	// all fragments in the data center with the lowest risk is always enough.
+	lo, hi := 0.0, float64(fragments)*math.Log(float64(slices.Min(risks)))
+	for i := 0; i < searchIterations && lo < hi; i++ {
+		mid := lo + (hi-lo)/2
+		if mid == lo || mid == hi {
+			break
+		}
+
+		if capacity(logs, mid, fragments) < fragments {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	// Every data center gets the fragments whose risk stays below lo,
+	// which are the smallest risks of all and so part of the optimal distribution.
+	limit := lo * (1 - searchMargin)
+	dataCenters := make([]dataCenter, len(risks))
+	stored := 0
+	for i, risk := range risks {
+		dataCenters[i] = dataCenter{
+			baseRisk:  risk,
+			fragments: fits(logs[i], limit, fragments),
+			capacity:  math.MaxInt,
+			index:     i,
+		}
+		stored += dataCenters[i].fragments
+	}
+
+	dataCentersHeap := newMinHeap(dataCenters)
+	allocation := newAllocation(len(risks))
+	for ; stored < fragments; stored++ {
+		allocation.Bottleneck = dataCentersHeap.peekDataCenter().index
+		dataCentersHeap.storeFragment()
+	}
+
+	allocation.record(*dataCentersHeap)
+
+	return allocation, nil
+}
+
+// capacity returns the number of fragments the data centers can hold with the risk
+// at most e^limit, capped at fragments.
+func capacity(logs []float64, limit float64, fragments int) int {
+	total := 0
+	for _, l := range logs {
+		total += fits(l, limit, fragments-total)
+		if total >= fragments {
+			return fragments
+		}
+	}
+
+	return total
+}
+
+// fits returns floor(limit/log), the number of fragments a data center can hold
+// with the risk at most e^limit, capped at most.
+func fits(log, limit float64, most int) int {
+	n := math.Floor(limit / log)
+	if n >= float64(most) {
+		return most
+	}
+
+	return int(n)
+}
@@ -0,0 +1,152 @@
+package allocation
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestDistributeBinarySearch(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		risks         []int
+		fragments     int
+		expCounts     []int
+		expRisk       string
+		expBottleneck int
+		expErr        error
+	}{
+		{
+			desc:          "Success_ReadmeExample",
+			risks:         []int{10, 20, 30},
+			fragments:     5,
+			expCounts:     []int{2, 2, 1},
+			expRisk:       "400",
+			expBottleneck: 1,
+		},
+		{
+			desc:          "Success_UnsortedRisks",
+			risks:         []int{20, 10, 2, 15},
+			fragments:     3,
+			expCounts:     []int{0, 0, 3, 0},
+			expRisk:       "8",
+			expBottleneck: 2,
+		},
+		{
+			desc:          "Success_HugeRisks",
+			risks:         []int{2, 4},
+			fragments:     1000,
+			expCounts:     []int{667, 333},
+			expRisk:       "2^667",
+			expBottleneck: 0,
+		},
+		{
+			// 2^x = 3^y needs x/y = log2(3), about 1.585
+			desc:          "Success_BillionsOfFragments",
+			risks:         []int{2, 3},
+			fragments:     5_000_000_000,
+			expCounts:     []int{3_065_735_964, 1_934_264_036},
+			expRisk:       "2^3065735964",
+			expBottleneck: 0,
+		},
+		{
+			desc:          "AllFragments_InOneDataCenter_ShouldSucceed",
+			risks:         []int{10, 20, 30, 1},
+			fragments:     1_000_000_000,
+			expCounts:     []int{0, 0, 0, 1_000_000_000},
+			expRisk:       "1",
+			expBottleneck: 3,
+		},
+		{
+			desc:          "ZeroFragments_ShouldSucceed",
+			risks:         []int{10, 20},
+			fragments:     0,
+			expCounts:     []int{0, 0},
+			expRisk:       "1",
+			expBottleneck: 0,
+		},
+		{
+			desc:      "EmptyRisks_ShouldFail",
+			risks:     []int{},
+			fragments: 5,
+			expErr:    ErrEmptyDataCenters,
+		},
+		{
+			desc:      "ZeroRisk_ShouldFail",
+			risks:     []int{0, 2},
+			fragments: 5,
+			expErr:    ErrNonPositiveRisk,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			act, err := DistributeBinarySearch(tc.risks, tc.fragments)
+			if tc.expErr != nil {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+			th.AssertNilError(t, err)
+			th.AssertEqualIntSlices(t, act.Counts, tc.expCounts)
+			th.AssertEqualStrings(t, act.MaxRisk().String(), tc.expRisk)
+			th.AssertEqualInts(t, act.Bottleneck, tc.expBottleneck)
+		})
+	}
+}
+
+func TestDistributeBinarySearch_MatchesHeap(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		risks := make([]int, 1+rnd.Intn(8))
+		for j := range risks {
+			risks[j] = 2 + rnd.Intn(50)
+		}
+		fragments := rnd.Intn(2000)
+
+		t.Run(fmt.Sprint(risks, fragments), func(t *testing.T) {
+			exp, err := Distribute(risks, fragments)
+			th.AssertNilError(t, err)
+			act, err := DistributeBinarySearch(risks, fragments)
+			th.AssertNilError(t, err)
+
+			th.AssertEqualInts(t, act.MaxRisk().Cmp(exp.MaxRisk()), 0)
+			th.AssertEqualInts(t, act.Risks[act.Bottleneck].Cmp(act.MaxRisk()), 0)
+
+			stored := 0
+			for _, count := range act.Counts {
+				stored += count
+			}
+			th.AssertEqualInts(t, stored, fragments)
+		})
+	}
+}
+
+func BenchmarkDistribute(b *testing.B) {
+	risks := benchmarkRisks(100)
+	for _, fragments := range []int{1_000, 1_000_000} {
+		b.Run(fmt.Sprintf("Heap/fragments=%d", fragments), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Distribute(risks, fragments)
+			}
+		})
+	}
+	for _, fragments := range []int{1_000, 1_000_000, 1_000_000_000_000} {
+		b.Run(fmt.Sprintf("BinarySearch/fragments=%d", fragments), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				DistributeBinarySearch(risks, fragments)
+			}
+		})
+	}
+}
+
+// benchmarkRisks returns n random risks between 2 and 1000.
+func benchmarkRisks(n int) []int {
+	rnd := rand.New(rand.NewSource(1))
+	risks := make([]int, n)
+	for i := range risks {
+		risks[i] = 2 + rnd.Intn(999)
+	}
+
+	return risks
+}
@@ -1,25 +1,55 @@
 package allocation
 
-import "container/heap"
-
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	ErrEmptyDataCenters  = errors.New("there are no data centers")
+	ErrNonPositiveRisk   = errors.New("risk must be positive")
+	ErrNegativeFragments = errors.New("number of fragments must not be negative")
+)
+
+// dataCenter keeps the number of fragments stored in the data center.
+// Its risk is baseRisk^fragments, kept in exponent form so it never overflows.
 type dataCenter struct {
-	baseRisk   int
-	actualRisk int
+	baseRisk  int
+	fragments int
+	capacity  int // maximum number of fragments
+	index     int // position in the input risks slice
 }
 
-// IncreaseRisk multiplies the DataCenter's actualRisk by its baseRisk.
-// This is called when new fragment is stored to the DataCenter.
+// risk returns the risk of the data center with the fragments stored so far.
+func (dc *dataCenter) risk() Risk { return Risk{dc.baseRisk, dc.fragments} }
+
+// nextRisk returns the risk the data center will have once it stores the next fragment.
+func (dc *dataCenter) nextRisk() Risk { return Risk{dc.baseRisk, dc.fragments + 1} }
+
+// full reports whether the data center cannot store more fragments.
+func (dc *dataCenter) full() bool { return dc.fragments >= dc.capacity }
+
+// IncreaseRisk stores a new fragment in the DataCenter, multiplying its risk by its baseRisk.
 func (dc *dataCenter) IncreaseRisk() {
-	dc.actualRisk = dc.actualRisk * dc.baseRisk
+	dc.fragments++
 }
 
 type minHeap []dataCenter
 
 func initMinHeap(risks []int) *minHeap {
-	mh := make(minHeap, len(risks))
+	dataCenters := make([]dataCenter, len(risks))
 	for i := 0; i < len(risks); i++ {
-		mh[i] = dataCenter{risks[i], risks[i]}
+		dataCenters[i] = dataCenter{baseRisk: risks[i], capacity: math.MaxInt, index: i}
 	}
+
+	return newMinHeap(dataCenters)
+}
+
+// newMinHeap creates the MinHeap of data centers which can already store some fragments.
+func newMinHeap(dataCenters []dataCenter) *minHeap {
+	mh := minHeap(dataCenters)
 	heap.Init(&mh)
 
 	return &mh
@@ -28,7 +58,7 @@ func initMinHeap(risks []int) *minHeap {
 // sort.Interface methods
 func (mh minHeap) Len() int           { return len(mh) }
 func (mh minHeap) Swap(i, j int)      { mh[i], mh[j] = mh[j], mh[i] }
-func (mh minHeap) Less(i, j int) bool { return mh[i].actualRisk < mh[j].actualRisk }
+func (mh minHeap) Less(i, j int) bool { return mh[i].nextRisk().Cmp(mh[j].nextRisk()) < 0 }
 
 // heap.Interface methods
 func (mh *minHeap) Push(x any) { *mh = append(*mh, x.(dataCenter)) }
@@ -49,18 +79,87 @@ func (mh minHeap) peekDataCenter() *dataCenter { return &mh[0] }
 
 // storeFragment stores a new fragment in the DataCenter at the top of the MinHeap,
 // increasing its risk and fixing the heap.
-// Returns the original risk before the update.
-func (mh *minHeap) storeFragment() int {
+// Returns the risk of the DataCenter with the new fragment.
+func (mh *minHeap) storeFragment() Risk {
 	// retrieve the data center which has minimal acquired risk
 	dc := mh.peekDataCenter()
-	// keep the current risk
-	originalRisk := dc.actualRisk
 	// increase the risk, because new fragment is stored in the data center
 	dc.IncreaseRisk()
+	risk := dc.risk()
 	// heap element is mutated so the heap needs a fix
 	heap.Fix(mh, 0)
 
-	return originalRisk
+	return risk
+}
+
+// Allocation is the result of distributing fragments among data centers.
+// Counts and Risks are indexed like the input risks slice.
+type Allocation struct {
+	Counts     []int  // number of fragments stored in each data center
+	Risks      []Risk // risk of each data center, baseRisk^count
+	Bottleneck int    // index of the data center with the maximum risk, -1 without data centers
+}
+
+// newAllocation creates the Allocation for n data centers without a bottleneck.
+func newAllocation(n int) Allocation {
+	return Allocation{
+		Counts:     make([]int, n),
+		Risks:      make([]Risk, n),
+		Bottleneck: -1,
+	}
+}
+
+// record keeps the fragments stored in the data centers and their risks.
+func (a Allocation) record(dataCenters []dataCenter) {
+	for _, dc := range dataCenters {
+		a.Counts[dc.index] = dc.fragments
+		a.Risks[dc.index] = dc.risk()
+	}
+}
+
+// findBottleneck sets the bottleneck to the data center with the maximum risk.
+// Without fragments all data centers have risk 1, the first one is the bottleneck.
+func (a *Allocation) findBottleneck() {
+	if len(a.Risks) == 0 {
+		a.Bottleneck = -1
+		return
+	}
+
+	a.Bottleneck = 0
+	for i, risk := range a.Risks {
+		if a.Counts[i] > 0 && risk.Cmp(a.MaxRisk()) > 0 {
+			a.Bottleneck = i
+		}
+	}
+}
+
+// MaxRisk returns the risk of the bottleneck data center, 0 without data centers.
+func (a Allocation) MaxRisk() Risk {
+	if a.Bottleneck < 0 {
+		return Risk{Base: 0, Exponent: 1}
+	}
+
+	return a.Risks[a.Bottleneck]
+}
+
+// validate checks the risks of the data centers and the number of fragments.
+// A base risk of 0 would make a data center safer with fragments (0^n) than without (0^0 = 1),
+// so it is rejected together with the negative ones. A base risk of 1 is a data center without risk,
+// it keeps risk 1 with any number of fragments.
+func validate(risks []int, fragments int) error {
+	if len(risks) == 0 {
+		return ErrEmptyDataCenters
+	}
+	for i, risk := range risks {
+		if risk <= 0 {
+			return fmt.Errorf("%w: %d of data center %d", ErrNonPositiveRisk, risk, i)
+		}
+	}
+	if fragments < 0 {
+		return fmt.Errorf("%w: %d", ErrNegativeFragments, fragments)
+	}
+
+	return nil
 }
 
 // DistributeFragments distributes the fragments among data centers
@@ -68,25 +167,65 @@ func (mh *minHeap) storeFragment() int {
 // after all fragments have been distributed.
 //
 // Parameters:
-//   - risks: a slice of integers representing the initial risk values of each data center.
+//   - risks: a slice of integers representing the initial risk values of each data center, at least 1.
 //   - fragments: the number of fragments to distribute.
 //
 // Returns:
-//   - The minimized maximum risk value after distribution.
-func DistributeFragments(risks []int, fragments int) int {
+//   - The minimized maximum of baseRisk^count across the data centers after distribution.
+//     A data center without fragments has risk 1 (baseRisk^0), so 0 fragments give 1.
+//     A data center with base risk 1 takes all fragments with risk 1.
+//   - ErrEmptyDataCenters, ErrNonPositiveRisk or ErrNegativeFragments for invalid input.
+//   - ErrRiskOverflow if the risk does not fit in int, Distribute returns it in exponent form.
+func DistributeFragments(risks []int, fragments int) (int, error) {
+	allocation, err := Distribute(risks, fragments)
+	if err != nil {
+		return 0, err
+	}
+
+	return allocation.MaxRisk().Int()
+}
+
+// Distribute distributes the fragments among data centers represented by their risk values,
+// minimizing the maximum risk the same way as DistributeFragments.
+// Returns the number of fragments stored in each data center, their risks and the bottleneck.
+//
+// Parameters:
+//   - risks: a slice of integers representing the initial risk values of each data center, at least 1.
+//   - fragments: the number of fragments to distribute.
+//
+// Returns:
+//   - The Allocation, indexed like risks.
+//   - ErrEmptyDataCenters, ErrNonPositiveRisk or ErrNegativeFragments for invalid input.
+func Distribute(risks []int, fragments int) (Allocation, error) {
+	if err := validate(risks, fragments); err != nil {
+		return Allocation{}, err
+	}
+
+	allocation := newAllocation(len(risks))
 	dataCentersHeap := initMinHeap(risks)
 
 	// if there is a data center with base risk of 1
 	// => we can put all fragments there
-	if dataCentersHeap.peekDataCenter().baseRisk == 1 {
-		return 1
+	if dc := dataCentersHeap.peekDataCenter(); dc.baseRisk == 1 {
+		for i, risk := range risks {
+			allocation.Risks[i] = Risk{Base: risk}
+		}
+		allocation.Counts[dc.index] = fragments
+		allocation.Risks[dc.index].Exponent = fragments
+		allocation.Bottleneck = dc.index
+
+		return allocation, nil
 	}
 
-	totalRisk := 0
+	// Every fragment goes to the data center which will have the lowest risk with it.
+	// The stored risks never decrease, so the last one is the maximum.
+	allocation.Bottleneck = 0
 	for i := 0; i < fragments; i++ {
-		risk := dataCentersHeap.storeFragment()
-		totalRisk += risk
+		allocation.Bottleneck = dataCentersHeap.peekDataCenter().index
+		dataCentersHeap.storeFragment()
 	}
 
-	return totalRisk
+	allocation.record(*dataCentersHeap)
+
+	return allocation, nil
 }
@@ -24,9 +24,9 @@ func TestMinIntHeap(t *testing.T) {
 			expOut: []int{},
 		},
 		{
-			desc:   "NegativeRisks_ShouldSucceed",
-			risks:  []int{2, 77, 30, 20, -10},
-			expOut: []int{-10, 2, 20, 30, 77},
+			desc:   "EqualRisks_ShouldSucceed",
+			risks:  []int{2, 77, 30, 20, 2},
+			expOut: []int{2, 2, 20, 30, 77},
 		},
 	}
 
@@ -45,18 +45,63 @@ func TestDistributeFragments(t *testing.T) {
 		risks     []int
 		fragments int
 		expRisk   int
+		expErr    error
 	}{
 		{
 			desc:      "Success#1",
 			risks:     []int{20, 10, 2, 15},
 			fragments: 3,
-			expRisk:   14,
+			expRisk:   8,
 		},
 		{
-			desc:      "Success#2",
+			// 10^2, 20^2 and 30^1, storing 3 fragments in the first data center gives 10^3 = 1000
+			desc:      "Success_ReadmeExample",
 			risks:     []int{10, 20, 30},
 			fragments: 5,
-			expRisk:   560,
+			expRisk:   400,
+		},
+		{
+			desc:      "SingleDataCenter_ShouldSucceed",
+			risks:     []int{3},
+			fragments: 4,
+			expRisk:   81,
+		},
+		{
+			desc:      "ZeroFragments_ShouldReturn_One",
+			risks:     []int{10, 20, 30},
+			fragments: 0,
+			expRisk:   1,
+		},
+		{
+			desc:      "EmptyRisks_ShouldFail",
+			risks:     []int{},
+			fragments: 5,
+			expErr:    ErrEmptyDataCenters,
+		},
+		{
+			desc:      "NegativeRisk_ShouldFail",
+			risks:     []int{2, 77, 30, 20, -10},
+			fragments: 5,
+			expErr:    ErrNonPositiveRisk,
+		},
+		{
+			desc:      "ZeroRisk_ShouldFail",
+			risks:     []int{10, 0},
+			fragments: 5,
+			expErr:    ErrNonPositiveRisk,
+		},
+		{
+			// the base risk of 1 does not skip the validation of the other data centers
+			desc:      "BaseRiskOne_And_NegativeRisk_ShouldFail",
+			risks:     []int{1, -10},
+			fragments: 5,
+			expErr:    ErrNonPositiveRisk,
+		},
+		{
+			desc:      "NegativeFragments_ShouldFail",
+			risks:     []int{10, 20},
+			fragments: -1,
+			expErr:    ErrNegativeFragments,
 		},
 		{
 			desc:      "AllFragments_InOneDataCenter_ShouldSucceed",
@@ -64,22 +109,133 @@ func TestDistributeFragments(t *testing.T) {
 			fragments: 500,
 			expRisk:   1,
 		},
+		{
+			// 2^61 and 3^39, plain int arithmetic would overflow on 2^63 while comparing
+			desc:      "RiskCloseToMaxInt_ShouldSucceed",
+			risks:     []int{2, 3},
+			fragments: 100,
+			expRisk:   4052555153018976267,
+		},
+		{
+			desc:      "RiskOverflow_ShouldFail",
+			risks:     []int{30},
+			fragments: 13,
+			expErr:    ErrRiskOverflow,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			actRisk := DistributeFragments(tc.risks, tc.fragments)
+			actRisk, err := DistributeFragments(tc.risks, tc.fragments)
+			if tc.expErr != nil {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+			th.AssertNilError(t, err)
 			th.AssertEqualInts(t, actRisk, tc.expRisk)
 		})
 	}
 }
 
+func TestDistribute(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		risks         []int
+		fragments     int
+		expCounts     []int
+		expRisks      []int
+		expBottleneck int
+		expErr        error
+	}{
+		{
+			desc:          "Success_ReadmeExample",
+			risks:         []int{10, 20, 30},
+			fragments:     5,
+			expCounts:     []int{2, 2, 1},
+			expRisks:      []int{100, 400, 30},
+			expBottleneck: 1,
+		},
+		{
+			desc:          "Success_UnsortedRisks",
+			risks:         []int{20, 10, 2, 15},
+			fragments:     3,
+			expCounts:     []int{0, 0, 3, 0},
+			expRisks:      []int{1, 1, 8, 1},
+			expBottleneck: 2,
+		},
+		{
+			desc:          "AllFragments_InOneDataCenter_ShouldSucceed",
+			risks:         []int{10, 20, 30, 1},
+			fragments:     500,
+			expCounts:     []int{0, 0, 0, 500},
+			expRisks:      []int{1, 1, 1, 1},
+			expBottleneck: 3,
+		},
+		{
+			desc:          "ZeroFragments_ShouldSucceed",
+			risks:         []int{10, 20},
+			fragments:     0,
+			expCounts:     []int{0, 0},
+			expRisks:      []int{1, 1},
+			expBottleneck: 0,
+		},
+		{
+			// 2^667 and 4^333 = 2^666, far beyond int
+			desc:          "Success_HugeRisks",
+			risks:         []int{2, 4},
+			fragments:     1000,
+			expCounts:     []int{667, 333},
+			expRisks:      []int{-1, -1},
+			expBottleneck: 0,
+		},
+		{
+			desc:      "EmptyRisks_ShouldFail",
+			risks:     []int{},
+			fragments: 5,
+			expErr:    ErrEmptyDataCenters,
+		},
+		{
+			desc:      "NegativeFragments_ShouldFail",
+			risks:     []int{10, 20},
+			fragments: -5,
+			expErr:    ErrNegativeFragments,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			act, err := Distribute(tc.risks, tc.fragments)
+			if tc.expErr != nil {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+			th.AssertNilError(t, err)
+			th.AssertEqualIntSlices(t, act.Counts, tc.expCounts)
+			th.AssertEqualIntSlices(t, riskInts(act.Risks), tc.expRisks)
+			th.AssertEqualInts(t, act.Bottleneck, tc.expBottleneck)
+		})
+	}
+}
+
+// riskInts converts the risks to ints, -1 for the risks which do not fit.
+func riskInts(risks []Risk) []int {
+	result := make([]int, len(risks))
+	for i, risk := range risks {
+		v, err := risk.Int()
+		if err != nil {
+			v = -1
+		}
+		result[i] = v
+	}
+
+	return result
+}
+
 func popAllNodes(t *testing.T, mh *minHeap) []int {
 	t.Helper()
 
 	result := make([]int, mh.Len())
 	i := 0
 	for mh.Len() > 0 {
-		result[i] = heap.Pop(mh).(dataCenter).actualRisk
+		result[i] = heap.Pop(mh).(dataCenter).baseRisk
 		i++
 	}
 
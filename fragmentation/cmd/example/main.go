@@ -8,9 +8,9 @@ import (
 
 func main() {
 	fragments := make(map[int]f.Fragment)
-	fragments[3] = f.Fragment{Data: "!", Hash: f.SimpleHash("!")}
-	fragments[2] = f.Fragment{Data: "World", Hash: f.SimpleHash("World")}
-	fragments[1] = f.Fragment{Data: "Hello", Hash: f.SimpleHash("Hello")}
+	fragments[3] = f.NewFragment("!")
+	fragments[2] = f.NewFragment("World", f.WithHasher(f.StdlibSHA256Hasher))
+	fragments[1] = f.NewFragment("Hello")
 
 	data, err := f.ReconstructData(fragments)
 	if err != nil {
@@ -18,4 +18,16 @@ func main() {
 	}
 
 	fmt.Printf("Reconstructed data: %v\n", data)
+
+	// legacy fragments without the algorithm in the hash
+	legacy := make(map[int]f.Fragment)
+	legacy[2] = f.Fragment{Data: "World", Hash: f.BinarySimpleHash("World")}
+	legacy[1] = f.Fragment{Data: "Hello", Hash: f.BinarySimpleHash("Hello")}
+
+	data, err = f.ReconstructData(legacy)
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("Reconstructed legacy data: %v\n", data)
 }
@@ -0,0 +1,126 @@
+package fragmentation
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// base62 is the alphabet of SimpleHash, HashLen base62 characters carry log2(62^30) ~ 178 bits.
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// multiply-rotate constants of the hash lanes, odd 64-bit numbers with well spread bits
+const (
+	prime1 uint64 = 0x9E3779B185EBCA87
+	prime2 uint64 = 0xC2B2AE3D27D4EB4F
+	prime3 uint64 = 0x165667B19E3779F9
+	prime4 uint64 = 0x85EBCA77C2B2AE63
+	prime5 uint64 = 0x27D4EB2F165667C5
+)
+
+// SimpleHash computes and returns a simple hash value for the provided data string.
+// The hash is intended for non-cryptographic purposes: three 64-bit lanes consume every 8 bytes
+// of the input with a multiply-rotate round, each lane also mixing in the previous one, so every
+// input byte reaches all 192 bits. The lanes are finalized with the murmur3 avalanche and encoded
+// as HashLen base62 characters, which keeps ~178 of the bits.
+//
+// Parameters:
+//   - data: the input string to hash.
+//
+// Returns:
+//   - A string of HashLen base62 characters representing the hash value of the input data.
+func SimpleHash(data string) string {
+	lanes := [3]uint64{prime1 ^ prime2, prime2 ^ prime3, prime3 ^ prime4}
+
+	for len(data) >= 8 {
+		mixBlock(&lanes, load64(data[:8]))
+		data = data[8:]
+	}
+	// the tail is marked by a 1 bit after its bytes, so trailing zero bytes still change the block
+	tail := uint64(1) << (8 * len(data))
+	for i := len(data) - 1; i >= 0; i-- {
+		tail |= uint64(data[i]) << (8 * i)
+	}
+	mixBlock(&lanes, tail)
+
+	// every lane ends up depending on the other two
+	a, b, c := lanes[0], lanes[1], lanes[2]
+	a = fmix64(a ^ bits.RotateLeft64(c, 29))
+	b = fmix64(b + a)
+	c = fmix64(c ^ b)
+	a = fmix64(a + c)
+	b = fmix64(b ^ a)
+
+	return encodeBase62([3]uint64{a, b, c})
+}
+
+// mixBlock mixes the 8-byte block into all lanes.
+func mixBlock(lanes *[3]uint64, block uint64) {
+	lanes[0] = round(lanes[0], block)
+	lanes[1] = round(lanes[1], block^lanes[0])
+	lanes[2] = round(lanes[2], block+lanes[1])
+}
+
+// round is the xxHash64 accumulator round.
+func round(acc, input uint64) uint64 {
+	acc += input * prime2
+	acc = bits.RotateLeft64(acc, 31)
+
+	return acc * prime1
+}
+
+// fmix64 is the murmur3 finalizer, every input bit affects every output bit.
+func fmix64(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xFF51AFD7ED558CCD
+	k ^= k >> 33
+	k *= 0xC4CEB9FE1A85EC53
+	k ^= k >> 33
+
+	return k ^ prime5
+}
+
+// load64 reads the 8 bytes of s as a little-endian number.
+func load64(s string) uint64 {
+	_ = s[7] // bounds check hint
+	return uint64(s[0]) | uint64(s[1])<<8 | uint64(s[2])<<16 | uint64(s[3])<<24 |
+		uint64(s[4])<<32 | uint64(s[5])<<40 | uint64(s[6])<<48 | uint64(s[7])<<56
+}
+
+// encodeBase62 returns the HashLen lowest base62 digits of the 192-bit number, words[0] most significant.
+func encodeBase62(words [3]uint64) string {
+	var out [HashLen]byte
+	for i := HashLen - 1; i >= 0; i-- {
+		// long division of the whole number by 62, the remainder is the next digit
+		var rem uint64
+		for j := range words {
+			words[j], rem = bits.Div64(rem, words[j], 62)
+		}
+		out[i] = base62[rem]
+	}
+
+	return string(out[:])
+}
+
+// FNV-1a 64-bit parameters
+const (
+	fnvOffset64 uint64 = 14695981039346656037
+	fnvPrime64  uint64 = 1099511628211
+)
+
+// FNV1aHash returns the 64-bit FNV-1a hash of the data as 16 hex characters.
+// It is fast and simple, but carries only 64 bits.
+//
+// Parameters:
+//   - data: the input string to hash.
+//
+// Returns:
+//   - A string of 16 lowercase hex characters.
+func FNV1aHash(data string) string {
+	h := fnvOffset64
+	for i := 0; i < len(data); i++ {
+		h ^= uint64(data[i])
+		h *= fnvPrime64
+	}
+
+	return fmt.Sprintf("%016x", h)
+}
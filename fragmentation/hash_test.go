@@ -0,0 +1,116 @@
+package fragmentation
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestSimpleHash(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		input  string // string to be hashed
+		expOut string // expected hash
+	}{
+		{
+			desc:   "Successful_Hashing",
+			input:  "Hello",
+			expOut: "YZVJCXKGYGiDC2EL74s4rktYlZb8pD",
+		},
+		{
+			desc:   "LongerThanBlock_ShouldSucceed",
+			input:  "Hello, World!",
+			expOut: "uC9MoK5b6SSs4bpsMQYkif7aHoxl6L",
+		},
+		{
+			desc:   "EmptyString_ShouldSucceed",
+			input:  "",
+			expOut: "o6e0rWekbvWwsiOtQo98opcUHinOM5",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			actHash := SimpleHash(tc.input)
+			th.AssertEqualInts(t, len(actHash), HashLen)
+			th.AssertEqualStrings(t, actHash, tc.expOut)
+			th.AssertEqualInts(t, len(strings.Trim(actHash, base62)), 0)
+		})
+	}
+}
+
+func TestSimpleHash_DistinguishesBinaryCollisions(t *testing.T) {
+	// the first 30 bits of the polynomial are the same for both
+	th.AssertEqualStrings(t, BinarySimpleHash("300001"), BinarySimpleHash("300002"))
+	if SimpleHash("300001") == SimpleHash("300002") {
+		t.Errorf("expected different hashes for %q and %q", "300001", "300002")
+	}
+}
+
+func TestSimpleHash_NoCollisions(t *testing.T) {
+	inputs := []string{"", "\x00", "\x00\x00", "12345678", "12345678\x00"}
+	for i := 0; i < 100_000; i++ {
+		inputs = append(inputs, fmt.Sprint(i), strings.Repeat("x", i%100)+fmt.Sprint(i))
+	}
+
+	seen := make(map[string]string, len(inputs))
+	for _, input := range inputs {
+		hash := SimpleHash(input)
+		if other, ok := seen[hash]; ok && other != input {
+			t.Fatalf("expected different hashes for %q and %q, got %s", other, input, hash)
+		}
+		seen[hash] = input
+	}
+}
+
+func TestFNV1aHash(t *testing.T) {
+	testCases := []struct {
+		input  string
+		expOut string
+	}{
+		{input: "", expOut: "cbf29ce484222325"},
+		{input: "a", expOut: "af63dc4c8601ec8c"},
+		{input: "foobar", expOut: "85944171f73967e8"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			th.AssertEqualStrings(t, FNV1aHash(tc.input), tc.expOut)
+		})
+	}
+}
+
+// We can use the result to benchmark against other hashing algorithms
+func BenchmarkSimpleHash(b *testing.B) {
+	testCases := []struct {
+		desc  string
+		input string
+	}{
+		{
+			desc:  "Regular_Input",
+			input: "Benchmark",
+		},
+		{
+			desc:  "MidSize_Input",
+			input: strings.Repeat("xyz", math.MaxInt16),
+		},
+		{
+			desc:  "LargeSize_Input",
+			input: strings.Repeat("xyz", math.MaxInt32),
+		},
+	}
+
+	for _, tc := range testCases {
+		b.Run(tc.desc, func(b *testing.B) {
+			for b.Loop() {
+				SimpleHash(tc.input)
+			}
+		})
+		b.Run("Binary_"+tc.desc, func(b *testing.B) {
+			for b.Loop() {
+				BinarySimpleHash(tc.input)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package fragmentation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrUnknownHasher     = errors.New("hash algorithm is not registered")
+	ErrDuplicateHasher   = errors.New("hash algorithm is already registered")
+	ErrInvalidHasherName = errors.New("hash algorithm name or version is invalid")
+	ErrRejectedHasher    = errors.New("hash algorithm is not accepted")
+)
+
+// Hasher is a named hash algorithm used to verify the integrity of the fragments.
+// A change of the algorithm which changes its hashes needs a new version,
+// so the fragments hashed with the previous one can still be verified.
+type Hasher interface {
+	// Name is the name the algorithm is registered with.
+	Name() string
+	// Version is the version of the algorithm, starting at 1.
+	Version() int
+	// Hash returns the hash of the data.
+	Hash(data string) string
+}
+
+// The registered hash algorithms.
+var (
+	SimpleHasher       = NewHasher("simple", 1, SimpleHash)
+	BinaryHasher       = NewHasher("binary", 1, BinarySimpleHash)
+	FNV1aHasher        = NewHasher("fnv1a", 1, FNV1aHash)
+	SHA256Hasher       = NewHasher("sha256-scratch", 1, SHA256Hash)
+	StdlibSHA256Hasher = NewHasher("sha256", 1, stdlibSHA256) // crypto/sha256, for production
+)
+
+// hasherID identifies a version of a hash algorithm in the registry.
+type hasherID struct {
+	name    string
+	version int
+}
+
+var registry = struct {
+	sync.RWMutex
+	hashers map[hasherID]Hasher
+}{hashers: make(map[hasherID]Hasher)}
+
+func init() {
+	for _, h := range []Hasher{SimpleHasher, BinaryHasher, FNV1aHasher, SHA256Hasher, StdlibSHA256Hasher} {
+		if err := RegisterHasher(h); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// funcHasher is a Hasher calling a hash function.
+type funcHasher struct {
+	name    string
+	version int
+	hash    func(string) string
+}
+
+func (h funcHasher) Name() string { return h.name }
+
+func (h funcHasher) Version() int { return h.version }
+
+func (h funcHasher) Hash(data string) string { return h.hash(data) }
+
+// NewHasher returns a Hasher with the name and version calling the hash function.
+// The Hasher can be used directly or registered with RegisterHasher.
+func NewHasher(name string, version int, hash func(data string) string) Hasher {
+	return funcHasher{name: name, version: version, hash: hash}
+}
+
+// RegisterHasher adds the hash algorithm to the registry under its name and version.
+// The name is the prefix of the versioned hashes, so it cannot be empty or contain ':'.
+//
+// Returns:
+//   - ErrInvalidHasherName for an invalid name or a version below 1.
+//   - ErrDuplicateHasher if the same version of the algorithm is already registered.
+func RegisterHasher(h Hasher) error {
+	if h.Name() == "" || strings.Contains(h.Name(), hashSeparator) || h.Version() < 1 {
+		return fmt.Errorf("%w: %q version %d", ErrInvalidHasherName, h.Name(), h.Version())
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	id := hasherID{h.Name(), h.Version()}
+	if _, ok := registry.hashers[id]; ok {
+		return fmt.Errorf("%w: %q version %d", ErrDuplicateHasher, h.Name(), h.Version())
+	}
+	registry.hashers[id] = h
+
+	return nil
+}
+
+// unregisterHasher removes the version of the hash algorithm from the registry.
+func unregisterHasher(name string, version int) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	delete(registry.hashers, hasherID{name, version})
+}
+
+// LookupHasher returns the latest registered version of the hash algorithm with the name.
+//
+// Returns:
+//   - ErrUnknownHasher if no algorithm with the name is registered.
+func LookupHasher(name string) (Hasher, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	var latest Hasher
+	for id, h := range registry.hashers {
+		if id.name == name && (latest == nil || id.version > latest.Version()) {
+			latest = h
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownHasher, name)
+	}
+
+	return latest, nil
+}
+
+// LookupHasherVersion returns the registered hash algorithm with the name and version.
+//
+// Returns:
+//   - ErrUnknownHasher if the version of the algorithm is not registered.
+func LookupHasherVersion(name string, version int) (Hasher, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	h, ok := registry.hashers[hasherID{name, version}]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q version %d", ErrUnknownHasher, name, version)
+	}
+
+	return h, nil
+}
+
+// Hashers returns the names of the registered hash algorithms in ascending order.
+func Hashers() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	names := make([]string, 0, len(registry.hashers))
+	for id := range registry.hashers {
+		names = append(names, id.name)
+	}
+	slices.Sort(names)
+
+	return slices.Compact(names)
+}
+
+// Option configures the reconstruction and the verification of the fragments.
+type Option func(*options)
+
+type options struct {
+	hasher   Hasher
+	accepted map[hasherID]bool // nil accepts every algorithm
+	legacy   bool              // accept hashes without a prefix
+}
+
+// WithHasher sets the hash algorithm of NewFragment and of the stored hashes without
+// an algorithm prefix instead of SimpleHash, so fragments hashed with another algorithm
+// can be read without rehashing them. A versioned hash is verified with the algorithm it names,
+// WithAcceptedHashers and WithoutLegacy restrict which ones are accepted.
+func WithHasher(h Hasher) Option {
+	return func(o *options) {
+		o.hasher = h
+	}
+}
+
+// WithAcceptedHashers accepts only the stored hashes of these versions of the algorithms,
+// the fragments with other hashes are tampered. It applies to the hashes without a prefix too:
+// a legacy binary hash needs BinaryHasher and other ones the algorithm set with WithHasher.
+func WithAcceptedHashers(hashers ...Hasher) Option {
+	return func(o *options) {
+		o.accepted = make(map[hasherID]bool, len(hashers))
+		for _, h := range hashers {
+			o.accepted[hasherID{h.Name(), h.Version()}] = true
+		}
+	}
+}
+
+// WithoutLegacy accepts only versioned hashes, the fragments with a hash without a prefix
+// (a legacy binary hash or a plain digest) are tampered.
+func WithoutLegacy() Option {
+	return func(o *options) {
+		o.legacy = false
+	}
+}
+
+// accepts reports whether the stored hashes of the version of the algorithm are accepted.
+func (o options) accepts(h Hasher) bool {
+	return o.accepted == nil || o.accepted[hasherID{h.Name(), h.Version()}]
+}
+
+// newOptions applies the options to the defaults.
+func newOptions(opts []Option) options {
+	o := options{hasher: SimpleHasher, legacy: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// stdlibSHA256 returns the hex encoded SHA-256 of the data with crypto/sha256.
+func stdlibSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,189 @@
+package fragmentation
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestLookupHasher(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		name    string
+		input   string
+		expHash string
+		expErr  error
+	}{
+		{
+			desc:    "Simple_ShouldSucceed",
+			name:    "simple",
+			input:   "Hello",
+			expHash: SimpleHash("Hello"),
+		},
+		{
+			desc:    "Binary_ShouldSucceed",
+			name:    "binary",
+			input:   "Hello",
+			expHash: "000011001100000001000001111000",
+		},
+		{
+			desc:    "FNV1a_ShouldSucceed",
+			name:    "fnv1a",
+			input:   "Hello",
+			expHash: FNV1aHash("Hello"),
+		},
+		{
+			desc:    "SHA256_ShouldSucceed",
+			name:    "sha256-scratch",
+			input:   "abc",
+			expHash: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		},
+		{
+			desc:    "StdlibSHA256_ShouldSucceed",
+			name:    "sha256",
+			input:   "abc",
+			expHash: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		},
+		{
+			desc:   "Unknown_ShouldFail",
+			name:   "md5",
+			expErr: ErrUnknownHasher,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			h, err := LookupHasher(tc.name)
+			if tc.expErr != nil {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+			th.AssertNilError(t, err)
+			th.AssertEqualStrings(t, h.Name(), tc.name)
+			th.AssertEqualStrings(t, h.Hash(tc.input), tc.expHash)
+		})
+	}
+}
+
+func TestRegisterHasher(t *testing.T) {
+	reverse := func(data string) string {
+		runes := []rune(data)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes)
+	}
+	reversed := NewHasher("test-reversed", 1, reverse)
+	reversedTwice := NewHasher("test-reversed", 2, func(data string) string { return reverse(reverse(data)) })
+
+	t.Cleanup(func() {
+		unregisterHasher("test-reversed", 1)
+		unregisterHasher("test-reversed", 2)
+	})
+	th.AssertNilError(t, RegisterHasher(reversed))
+	th.AssertNilError(t, RegisterHasher(reversedTwice))
+	th.AssertCorrectError(t, RegisterHasher(reversed), ErrDuplicateHasher)
+	th.AssertCorrectError(t, RegisterHasher(NewHasher("simple", 1, FNV1aHash)), ErrDuplicateHasher)
+	th.AssertCorrectError(t, RegisterHasher(NewHasher("", 1, FNV1aHash)), ErrInvalidHasherName)
+	th.AssertCorrectError(t, RegisterHasher(NewHasher("a:b", 1, FNV1aHash)), ErrInvalidHasherName)
+	th.AssertCorrectError(t, RegisterHasher(NewHasher("test-zero", 0, FNV1aHash)), ErrInvalidHasherName)
+
+	// the latest version by name, older ones by name and version
+	h, err := LookupHasher("test-reversed")
+	th.AssertNilError(t, err)
+	th.AssertEqualInts(t, h.Version(), 2)
+	th.AssertEqualStrings(t, h.Hash("abc"), "abc")
+
+	h, err = LookupHasherVersion("test-reversed", 1)
+	th.AssertNilError(t, err)
+	th.AssertEqualStrings(t, h.Hash("abc"), "cba")
+
+	_, err = LookupHasherVersion("test-reversed", 3)
+	th.AssertCorrectError(t, err, ErrUnknownHasher)
+
+	names := Hashers()
+	th.AssertEqualStrings(t, names[0], "binary")
+	th.AssertEqualInts(t, len(slices.Compact(slices.Clone(names))), len(names))
+}
+
+func TestReconstructData_WithHasher(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		hasher     Hasher
+		hashedWith Hasher // algorithm the fragments were hashed with
+		expOut     string
+		shouldFail bool
+		expErr     error
+	}{
+		{
+			desc:       "DefaultHasher_ShouldSucceed",
+			hashedWith: SimpleHasher,
+			expOut:     "HelloWorld!",
+		},
+		{
+			desc:       "SameHasher_ShouldSucceed",
+			hasher:     StdlibSHA256Hasher,
+			hashedWith: StdlibSHA256Hasher,
+			expOut:     "HelloWorld!",
+		},
+		{
+			desc:       "LegacyHasher_ShouldSucceed",
+			hasher:     BinaryHasher,
+			hashedWith: BinaryHasher,
+			expOut:     "HelloWorld!",
+		},
+		{
+			desc:       "OtherHasher_ShouldFail",
+			hasher:     FNV1aHasher,
+			hashedWith: SimpleHasher,
+			shouldFail: true,
+			expErr:     ErrTamperedData,
+		},
+		{
+			desc:       "DefaultHasher_OtherAlgorithm_ShouldFail",
+			hashedWith: SHA256Hasher,
+			shouldFail: true,
+			expErr:     ErrTamperedData,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			fragments := make(map[int]Fragment)
+			for i, data := range []string{"Hello", "World", "!"} {
+				fragments[i+1] = Fragment{Data: data, Hash: tc.hashedWith.Hash(data)}
+			}
+
+			var opts []Option
+			if tc.hasher != nil {
+				opts = append(opts, WithHasher(tc.hasher))
+			}
+			data, err := ReconstructData(fragments, opts...)
+			assertReconstruction(t, data, err, tc.expOut, tc.shouldFail, tc.expErr, nil)
+
+			_, err = VerifyFragments(fragments, len(fragments), opts...)
+			if tc.shouldFail {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+			th.AssertNilError(t, err)
+		})
+	}
+}
+
+func BenchmarkHashers(b *testing.B) {
+	input := strings.Repeat("xyz", math.MaxInt16)
+	for _, name := range Hashers() {
+		h, err := LookupHasher(name)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				h.Hash(input)
+			}
+		})
+	}
+}
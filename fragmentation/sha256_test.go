@@ -0,0 +1,54 @@
+package fragmentation
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestSHA256Hash(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		input  string
+		expOut string
+	}{
+		{
+			desc:   "EmptyString_ShouldSucceed",
+			input:  "",
+			expOut: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		{
+			desc:   "OneBlock_ShouldSucceed",
+			input:  "abc",
+			expOut: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		},
+		{
+			// the length does not fit in the block with the rest, the padding takes another block
+			desc:   "TwoBlocks_ShouldSucceed",
+			input:  "abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq",
+			expOut: "248d6a61d20638b8e5c026930c3e6039a33ce45964ff2167f6ecedd419db06c1",
+		},
+		{
+			desc:   "MillionChars_ShouldSucceed",
+			input:  strings.Repeat("a", 1_000_000),
+			expOut: "cdc76e5c9914fb9281a1c7e284d73e67f1809a48a497200e046d39ccc7112cd0",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			th.AssertEqualStrings(t, SHA256Hash(tc.input), tc.expOut)
+		})
+	}
+}
+
+func TestSHA256Hash_MatchesStdlib(t *testing.T) {
+	// every length around the block and padding boundaries
+	for n := 0; n <= 200; n++ {
+		input := strings.Repeat("xyz", n)[:n]
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			th.AssertEqualStrings(t, SHA256Hash(input), StdlibSHA256Hasher.Hash(input))
+		})
+	}
+}
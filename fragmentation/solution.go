@@ -13,7 +13,10 @@ const (
 )
 
 var (
-	ErrTamperedData = errors.New("data integrity verification failed")
+	ErrTamperedData      = errors.New("data integrity verification failed")
+	ErrMissingFragments  = errors.New("fragments are missing")
+	ErrDuplicateSequence = errors.New("fragment sequence number is duplicated")
+	ErrInvalidSequence   = errors.New("fragment sequence number is out of range")
 )
 
 type Fragment struct {
@@ -21,30 +24,104 @@ type Fragment struct {
 	Hash string
 }
 
-func (f *Fragment) isValid() bool {
-	return SimpleHash(f.Data) == f.Hash
+// SequencedFragment is a fragment together with its sequence number, as collected from the storage units.
+type SequencedFragment struct {
+	Sequence int
+	Fragment
 }
 
+// MissingFragmentsError lists the sequence numbers of the missing fragments.
+// It wraps ErrMissingFragments.
+type MissingFragmentsError struct {
+	Missing []int
+}
+
+func (e *MissingFragmentsError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrMissingFragments, e.Missing)
+}
+
+func (e *MissingFragmentsError) Unwrap() error { return ErrMissingFragments }
+
 // ReconstructData rebuilds the original data string from a map of fragments.
 // The input map should have fragment indices as keys and fragment values as values.
+// The sequence numbers start at 1 and must not have gaps, missing fragments after the last one
+// can only be detected with ReconstructDataWithCount.
 // The function returns the reconstructed data as a string, or an error if reconstruction fails.
+// The fragments are verified with the algorithm named by their versioned hash,
+// a legacy binary hash with BinarySimpleHash and other hashes with SimpleHash or the one set with WithHasher.
 //
 // Parameters:
 //   - input: a map where keys are fragment indices and values are fragment data.
+//   - opts: options like WithHasher.
 //
 // Returns:
 //   - The reconstructed data as a string.
-//   - An error if the reconstruction is unsuccessful (e.g., missing fragments or invalid input).
-func ReconstructData(input map[int]Fragment) (string, error) {
-	var sb strings.Builder
-
+//   - An error if the reconstruction is unsuccessful:
+//     ErrInvalidSequence for a key below 1, *MissingFragmentsError for gaps or ErrTamperedData.
+func ReconstructData(input map[int]Fragment, opts ...Option) (string, error) {
 	// we need the sorted keys to reconstruct the data in proper order
 	sortedKeys := getSortedKeys(input)
 
+	count := 0
+	if len(sortedKeys) > 0 {
+		count = sortedKeys[len(sortedKeys)-1]
+	}
+
+	return reconstruct(input, sortedKeys, count, newOptions(opts))
+}
+
+// ReconstructDataWithCount rebuilds the original data string from a map of fragments
+// like ReconstructData, knowing the data was split into count fragments with sequence numbers 1 to count.
+//
+// Parameters:
+//   - input: a map where keys are fragment indices and values are fragment data.
+//   - count: the number of fragments the data was split into.
+//   - opts: options like WithHasher.
+//
+// Returns:
+//   - The reconstructed data as a string.
+//   - An error if the reconstruction is unsuccessful:
+//     ErrInvalidSequence for a negative count or a key outside of 1 to count, *MissingFragmentsError or ErrTamperedData.
+func ReconstructDataWithCount(input map[int]Fragment, count int, opts ...Option) (string, error) {
+	return reconstruct(input, getSortedKeys(input), count, newOptions(opts))
+}
+
+// ReconstructFragments rebuilds the original data string from the fragments in any order
+// like ReconstructDataWithCount.
+//
+// Parameters:
+//   - fragments: the fragments with their sequence numbers.
+//   - count: the number of fragments the data was split into.
+//   - opts: options like WithHasher.
+//
+// Returns:
+//   - The reconstructed data as a string.
+//   - An error if the reconstruction is unsuccessful: ErrDuplicateSequence if two fragments
+//     have the same sequence number, or an error of ReconstructDataWithCount.
+func ReconstructFragments(fragments []SequencedFragment, count int, opts ...Option) (string, error) {
+	input := make(map[int]Fragment, len(fragments))
+	for _, fragment := range fragments {
+		if _, ok := input[fragment.Sequence]; ok {
+			return "", fmt.Errorf("%w: %d", ErrDuplicateSequence, fragment.Sequence)
+		}
+		input[fragment.Sequence] = fragment.Fragment
+	}
+
+	return ReconstructDataWithCount(input, count, opts...)
+}
+
+// reconstruct checks the sequence numbers 1 to count and concatenates the valid fragments.
+func reconstruct(input map[int]Fragment, sortedKeys []int, count int, o options) (string, error) {
+	if err := checkSequence(sortedKeys, count); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+
 	for _, key := range sortedKeys {
 		fragment := input[key]
-		if !fragment.isValid() {
-			return "", ErrTamperedData
+		if _, err := fragment.check(o); err != nil {
+			return "", err
 		}
 		sb.WriteString(fragment.Data)
 	}
@@ -52,15 +129,16 @@ func ReconstructData(input map[int]Fragment) (string, error) {
 	return sb.String(), nil
 }
 
-// SimpleHash computes and returns a simple hash value for the provided data string.
-// The specific hash algorithm used is implementation-defined and intended for non-cryptographic purposes.
+// BinarySimpleHash is the previous SimpleHash, kept for the fragments stored with it.
+// It relies on int overflow of a base-29 polynomial and emits the top 30 bits in binary,
+// so it carries at most 30 bits and long inputs collide easily.
 //
 // Parameters:
 //   - data: the input string to hash.
 //
 // Returns:
 //   - A string representing the hash value of the input data.
-func SimpleHash(data string) string {
+func BinarySimpleHash(data string) string {
 	result := 0
 
 	// ignore potential int overflow
@@ -83,6 +161,36 @@ func SimpleHash(data string) string {
 	return binary
 }
 
+// checkSequence checks the sorted keys are exactly the sequence numbers 1 to count.
+func checkSequence(sortedKeys []int, count int) error {
+	if count < 0 {
+		return fmt.Errorf("%w: count %d", ErrInvalidSequence, count)
+	}
+	if len(sortedKeys) > 0 && sortedKeys[0] < 1 {
+		return fmt.Errorf("%w: %d", ErrInvalidSequence, sortedKeys[0])
+	}
+	if len(sortedKeys) > 0 && sortedKeys[len(sortedKeys)-1] > count {
+		return fmt.Errorf("%w: %d of %d fragments", ErrInvalidSequence, sortedKeys[len(sortedKeys)-1], count)
+	}
+	if len(sortedKeys) == count {
+		return nil
+	}
+
+	missing := make([]int, 0, count-len(sortedKeys))
+	next := 1
+	for _, key := range sortedKeys {
+		for ; next < key; next++ {
+			missing = append(missing, next)
+		}
+		next = key + 1
+	}
+	for ; next <= count; next++ {
+		missing = append(missing, next)
+	}
+
+	return &MissingFragmentsError{Missing: missing}
+}
+
 func getSortedKeys(input map[int]Fragment) []int {
 	keys := make([]int, len(input))
 
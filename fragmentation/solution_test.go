@@ -1,14 +1,13 @@
 package fragmentation
 
 import (
-	"math"
-	"strings"
+	"errors"
 	"testing"
 
 	th "developers-challenge/pkg/testhelpers"
 )
 
-func TestSimpleHash(t *testing.T) {
+func TestBinarySimpleHash(t *testing.T) {
 	testCases := []struct {
 		desc   string
 		input  string // string to be hashed
@@ -30,90 +29,201 @@ func TestSimpleHash(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			actHash := SimpleHash(tc.input)
+			actHash := BinarySimpleHash(tc.input)
 			th.AssertEqualInts(t, len(actHash), tc.expLen)
 			th.AssertEqualStrings(t, actHash, tc.expOut)
 		})
 	}
 }
 
-// We can use the result to benchmark against other hashing algorithms
-func BenchmarkSimpleHash(b *testing.B) {
+func TestReconstructData(t *testing.T) {
 	testCases := []struct {
-		desc  string
-		input string
+		desc       string
+		fragments  map[int]Fragment
+		expOut     string
+		shouldFail bool
+		expErr     error
+		expMissing []int
 	}{
 		{
-			desc:  "Regular_Input",
-			input: "Benchmark",
+			desc:      "Successful_Reconstruction",
+			fragments: initTestInput(),
+			expOut:    "HelloWorld!",
 		},
 		{
-			desc:  "MidSize_Input",
-			input: strings.Repeat("xyz", math.MaxInt16),
+			desc: "TamperedFragments_ShouldFailWith_ErrTamperedData",
+			fragments: func() map[int]Fragment {
+				fragments := initTestInput()
+				fragments[1] = Fragment{"tampered", "000011001100000001000001111000"}
+				return fragments
+			}(),
+			shouldFail: true,
+			expErr:     ErrTamperedData,
 		},
 		{
-			desc:  "LargeSize_Input",
-			input: strings.Repeat("xyz", math.MaxInt32),
+			desc:      "NilFragments_ShouldReturn_EmptyString",
+			fragments: nil,
+			expOut:    "",
+		},
+		{
+			desc: "MissingFragments_ShouldFailWith_ErrMissingFragments",
+			fragments: func() map[int]Fragment {
+				fragments := initTestInput()
+				fragments[6] = Fragment{Data: "?", Hash: SimpleHash("?")}
+				delete(fragments, 2)
+				return fragments
+			}(),
+			shouldFail: true,
+			expErr:     ErrMissingFragments,
+			expMissing: []int{2, 4, 5},
+		},
+		{
+			desc: "ZeroSequence_ShouldFailWith_ErrInvalidSequence",
+			fragments: func() map[int]Fragment {
+				fragments := initTestInput()
+				fragments[0] = Fragment{Data: "?", Hash: SimpleHash("?")}
+				return fragments
+			}(),
+			shouldFail: true,
+			expErr:     ErrInvalidSequence,
+		},
+		{
+			desc: "NegativeSequence_ShouldFailWith_ErrInvalidSequence",
+			fragments: func() map[int]Fragment {
+				fragments := initTestInput()
+				fragments[-1] = Fragment{Data: "?", Hash: SimpleHash("?")}
+				return fragments
+			}(),
+			shouldFail: true,
+			expErr:     ErrInvalidSequence,
 		},
 	}
-
 	for _, tc := range testCases {
-		b.Run(tc.desc, func(b *testing.B) {
-			for b.Loop() {
-				SimpleHash(tc.input)
-			}
+		tc := tc
+
+		t.Run(tc.desc, func(t *testing.T) {
+			data, err := ReconstructData(tc.fragments)
+			assertReconstruction(t, data, err, tc.expOut, tc.shouldFail, tc.expErr, tc.expMissing)
 		})
 	}
 }
 
-func TestReconstructData(t *testing.T) {
+func TestReconstructDataWithCount(t *testing.T) {
 	testCases := []struct {
 		desc       string
 		fragments  map[int]Fragment
+		count      int
 		expOut     string
 		shouldFail bool
 		expErr     error
+		expMissing []int
 	}{
 		{
 			desc:      "Successful_Reconstruction",
 			fragments: initTestInput(),
+			count:     3,
 			expOut:    "HelloWorld!",
 		},
 		{
-			desc: "TamperedFragments_ShouldFailWith_ErrTamperedData",
-			fragments: func() map[int]Fragment {
-				fragments := initTestInput()
-				fragments[0] = Fragment{"tampered", "000011001100000001000001111000"}
-				return fragments
-			}(),
+			desc:       "MissingLastFragments_ShouldFailWith_ErrMissingFragments",
+			fragments:  initTestInput(),
+			count:      5,
 			shouldFail: true,
-			expErr:     ErrTamperedData,
+			expErr:     ErrMissingFragments,
+			expMissing: []int{4, 5},
 		},
 		{
-			desc:      "NilFragments_ShouldReturn_EmptyString",
-			fragments: nil,
-			expOut:    "",
+			desc:       "NilFragments_ShouldFailWith_ErrMissingFragments",
+			fragments:  nil,
+			count:      2,
+			shouldFail: true,
+			expErr:     ErrMissingFragments,
+			expMissing: []int{1, 2},
+		},
+		{
+			desc:       "SequenceAboveCount_ShouldFailWith_ErrInvalidSequence",
+			fragments:  initTestInput(),
+			count:      2,
+			shouldFail: true,
+			expErr:     ErrInvalidSequence,
+		},
+		{
+			desc:       "NegativeCount_ShouldFailWith_ErrInvalidSequence",
+			fragments:  nil,
+			count:      -1,
+			shouldFail: true,
+			expErr:     ErrInvalidSequence,
 		},
 	}
 	for _, tc := range testCases {
-		tc := tc
-
 		t.Run(tc.desc, func(t *testing.T) {
-			data, err := ReconstructData(tc.fragments)
-			if tc.shouldFail {
-				th.AssertNotNilError(t, err)
-				th.AssertCorrectError(t, err, tc.expErr)
-			} else {
-				th.AssertNilError(t, err)
-			}
+			data, err := ReconstructDataWithCount(tc.fragments, tc.count)
+			assertReconstruction(t, data, err, tc.expOut, tc.shouldFail, tc.expErr, tc.expMissing)
+		})
+	}
+}
 
-			if data != tc.expOut {
-				th.AssertEqualStrings(t, data, tc.expOut)
-			}
+func TestReconstructFragments(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		fragments  []SequencedFragment
+		count      int
+		expOut     string
+		shouldFail bool
+		expErr     error
+	}{
+		{
+			desc: "Successful_Reconstruction",
+			fragments: []SequencedFragment{
+				{3, Fragment{Data: "!", Hash: SimpleHash("!")}},
+				{1, Fragment{Data: "Hello", Hash: SimpleHash("Hello")}},
+				{2, Fragment{Data: "World", Hash: SimpleHash("World")}},
+			},
+			count:  3,
+			expOut: "HelloWorld!",
+		},
+		{
+			desc: "DuplicateSequence_ShouldFailWith_ErrDuplicateSequence",
+			fragments: []SequencedFragment{
+				{1, Fragment{Data: "Hello", Hash: SimpleHash("Hello")}},
+				{2, Fragment{Data: "World", Hash: SimpleHash("World")}},
+				{1, Fragment{Data: "Hello", Hash: SimpleHash("Hello")}},
+			},
+			count:      2,
+			shouldFail: true,
+			expErr:     ErrDuplicateSequence,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			data, err := ReconstructFragments(tc.fragments, tc.count)
+			assertReconstruction(t, data, err, tc.expOut, tc.shouldFail, tc.expErr, nil)
 		})
 	}
 }
 
+// assertReconstruction checks the result of a reconstruction,
+// and the missing sequence numbers if the error is a *MissingFragmentsError.
+func assertReconstruction(t *testing.T, data string, err error, expOut string, shouldFail bool, expErr error, expMissing []int) {
+	t.Helper()
+
+	if shouldFail {
+		th.AssertNotNilError(t, err)
+		th.AssertCorrectError(t, err, expErr)
+	} else {
+		th.AssertNilError(t, err)
+	}
+
+	if data != expOut {
+		th.AssertEqualStrings(t, data, expOut)
+	}
+
+	var missingErr *MissingFragmentsError
+	if errors.As(err, &missingErr) {
+		th.AssertEqualIntSlices(t, missingErr.Missing, expMissing)
+	}
+}
+
 func initTestInput() map[int]Fragment {
 	fragments := make(map[int]Fragment)
 	fragments[3] = Fragment{Data: "!", Hash: SimpleHash("!")}
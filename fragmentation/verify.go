@@ -0,0 +1,113 @@
+package fragmentation
+
+import "fmt"
+
+// TamperedFragment is a fragment whose data does not match its hash.
+type TamperedFragment struct {
+	Sequence int
+	Expected string // hash stored with the fragment
+	Actual   string // hash of the fragment data, empty if the stored hash names an unknown or not accepted algorithm
+}
+
+// VerificationReport is the result of verifying all fragments, the sequence numbers in ascending order.
+type VerificationReport struct {
+	Valid    []int
+	Tampered []TamperedFragment
+	Missing  []int
+	Invalid  []int // sequence numbers outside of 1 to count
+}
+
+// OK reports whether all fragments are present and valid.
+func (r VerificationReport) OK() bool {
+	return len(r.Tampered) == 0 && len(r.Missing) == 0 && len(r.Invalid) == 0
+}
+
+// VerificationError carries the report of a failed verification. It wraps ErrTamperedData
+// if any fragment is tampered, ErrMissingFragments if any is missing
+// and ErrInvalidSequence for sequence numbers out of range.
+type VerificationError struct {
+	Report VerificationReport
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("fragment verification failed: %d tampered %v, missing %v, invalid %v",
+		len(e.Report.Tampered), tamperedSequences(e.Report.Tampered), e.Report.Missing, e.Report.Invalid)
+}
+
+func (e *VerificationError) Unwrap() []error {
+	var errs []error
+	if len(e.Report.Tampered) > 0 {
+		errs = append(errs, ErrTamperedData)
+	}
+	if len(e.Report.Missing) > 0 {
+		errs = append(errs, ErrMissingFragments)
+	}
+	if len(e.Report.Invalid) > 0 {
+		errs = append(errs, ErrInvalidSequence)
+	}
+
+	return errs
+}
+
+// VerifyFragments checks every fragment instead of stopping at the first invalid one like ReconstructData.
+//
+// Parameters:
+//   - input: a map where keys are fragment indices and values are fragment data.
+//   - count: the number of fragments the data was split into, sequence numbers 1 to count.
+//   - opts: options like WithHasher.
+//
+// Returns:
+//   - The VerificationReport with all valid, tampered, missing and invalid fragments.
+//   - *VerificationError carrying the same report if any fragment is not valid or missing.
+//   - ErrInvalidSequence without a report for a negative count.
+func VerifyFragments(input map[int]Fragment, count int, opts ...Option) (VerificationReport, error) {
+	if count < 0 {
+		return VerificationReport{}, fmt.Errorf("%w: count %d", ErrInvalidSequence, count)
+	}
+
+	o := newOptions(opts)
+	var report VerificationReport
+
+	next := 1
+	for _, key := range getSortedKeys(input) {
+		if key < 1 || key > count {
+			report.Invalid = append(report.Invalid, key)
+			continue
+		}
+
+		for ; next < key; next++ {
+			report.Missing = append(report.Missing, next)
+		}
+		next = key + 1
+
+		fragment := input[key]
+		if actual, err := fragment.check(o); err != nil {
+			report.Tampered = append(report.Tampered, TamperedFragment{
+				Sequence: key,
+				Expected: fragment.Hash,
+				Actual:   actual,
+			})
+			continue
+		}
+		report.Valid = append(report.Valid, key)
+	}
+	for ; next <= count; next++ {
+		report.Missing = append(report.Missing, next)
+	}
+
+	if !report.OK() {
+		return report, &VerificationError{Report: report}
+	}
+
+	return report, nil
+}
+
+// tamperedSequences returns the sequence numbers of the tampered fragments.
+func tamperedSequences(tampered []TamperedFragment) []int {
+	sequences := make([]int, len(tampered))
+	for i, t := range tampered {
+		sequences[i] = t.Sequence
+	}
+
+	return sequences
+}
@@ -0,0 +1,98 @@
+package fragmentation
+
+import (
+	"errors"
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestVerifyFragments(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		fragments   map[int]Fragment
+		count       int
+		expValid    []int
+		expTampered []TamperedFragment
+		expMissing  []int
+		expInvalid  []int
+		expErrs     []error
+	}{
+		{
+			desc:      "AllValid_ShouldSucceed",
+			fragments: initTestInput(),
+			count:     3,
+			expValid:  []int{1, 2, 3},
+		},
+		{
+			desc: "AllTampered_ShouldBeReported",
+			fragments: func() map[int]Fragment {
+				fragments := initTestInput()
+				fragments[1] = Fragment{Data: "Jello", Hash: SimpleHash("Hello")}
+				fragments[3] = Fragment{Data: "?", Hash: SimpleHash("!")}
+				return fragments
+			}(),
+			count:    3,
+			expValid: []int{2},
+			expTampered: []TamperedFragment{
+				{Sequence: 1, Expected: SimpleHash("Hello"), Actual: SimpleHash("Jello")},
+				{Sequence: 3, Expected: SimpleHash("!"), Actual: SimpleHash("?")},
+			},
+			expErrs: []error{ErrTamperedData},
+		},
+		{
+			desc: "TamperedMissingAndInvalid_ShouldBeReported",
+			fragments: func() map[int]Fragment {
+				fragments := initTestInput()
+				fragments[2] = Fragment{Data: "Word", Hash: SimpleHash("World")}
+				fragments[0] = Fragment{Data: "?", Hash: SimpleHash("?")}
+				fragments[9] = Fragment{Data: "?", Hash: SimpleHash("?")}
+				delete(fragments, 3)
+				return fragments
+			}(),
+			count:       5,
+			expValid:    []int{1},
+			expTampered: []TamperedFragment{{Sequence: 2, Expected: SimpleHash("World"), Actual: SimpleHash("Word")}},
+			expMissing:  []int{3, 4, 5},
+			expInvalid:  []int{0, 9},
+			expErrs:     []error{ErrTamperedData, ErrMissingFragments, ErrInvalidSequence},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			report, err := VerifyFragments(tc.fragments, tc.count)
+			th.AssertEqualIntSlices(t, report.Valid, tc.expValid)
+			th.AssertEqualIntSlices(t, report.Missing, tc.expMissing)
+			th.AssertEqualIntSlices(t, report.Invalid, tc.expInvalid)
+
+			th.AssertEqualInts(t, len(report.Tampered), len(tc.expTampered))
+			for i := range tc.expTampered {
+				if report.Tampered[i] != tc.expTampered[i] {
+					t.Errorf("exp: %v, act: %v", tc.expTampered, report.Tampered)
+				}
+			}
+
+			if len(tc.expErrs) == 0 {
+				th.AssertNilError(t, err)
+				return
+			}
+			for _, expErr := range tc.expErrs {
+				th.AssertCorrectError(t, err, expErr)
+			}
+
+			var verificationErr *VerificationError
+			if !errors.As(err, &verificationErr) {
+				t.Fatalf("exp: *VerificationError, act: %T", err)
+			}
+			th.AssertEqualInts(t, len(verificationErr.Report.Tampered), len(tc.expTampered))
+		})
+	}
+}
+
+func TestVerifyFragments_NegativeCount(t *testing.T) {
+	report, err := VerifyFragments(nil, -1)
+	th.AssertCorrectError(t, err, ErrInvalidSequence)
+	if !report.OK() || report.Valid != nil {
+		t.Errorf("exp: empty report, act: %v", report)
+	}
+}
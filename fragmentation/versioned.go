@@ -0,0 +1,112 @@
+package fragmentation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrMalformedHash = errors.New("versioned hash is malformed")
+
+// hashSeparator separates the algorithm name, the version and the digest of a versioned hash.
+const hashSeparator = ":"
+
+// HashInfo is a stored hash split into its parts.
+type HashInfo struct {
+	Name    string // algorithm name, empty for a hash without a prefix
+	Version int    // algorithm version, 0 for a hash without a prefix
+	Digest  string
+	Legacy  bool // a 30-character binary hash of BinarySimpleHash without a prefix
+}
+
+// EncodeHash returns the versioned hash of the data, the algorithm name and version
+// followed by the digest, like "simple:v1:<digest>".
+//
+// Parameters:
+//   - h: the hash algorithm.
+//   - data: the input string to hash.
+//
+// Returns:
+//   - The versioned hash of the data.
+func EncodeHash(h Hasher, data string) string {
+	return formatHash(h.Name(), h.Version(), h.Hash(data))
+}
+
+// ParseHash splits a stored hash into its parts. A hash without a prefix is
+// a legacy BinarySimpleHash if it has HashLen binary digits, otherwise a plain digest.
+//
+// Parameters:
+//   - hash: the stored hash.
+//
+// Returns:
+//   - The HashInfo of the hash.
+//   - ErrMalformedHash if the hash has a prefix without a valid name and version.
+func ParseHash(hash string) (HashInfo, error) {
+	if !strings.Contains(hash, hashSeparator) {
+		return HashInfo{Digest: hash, Legacy: isLegacyHash(hash)}, nil
+	}
+
+	parts := strings.SplitN(hash, hashSeparator, 3)
+	if len(parts) != 3 || parts[0] == "" || !strings.HasPrefix(parts[1], "v") {
+		return HashInfo{}, fmt.Errorf("%w: %q", ErrMalformedHash, hash)
+	}
+	version, err := strconv.Atoi(parts[1][1:])
+	if err != nil || version < 1 {
+		return HashInfo{}, fmt.Errorf("%w: version of %q", ErrMalformedHash, hash)
+	}
+
+	return HashInfo{Name: parts[0], Version: version, Digest: parts[2]}, nil
+}
+
+// NewFragment returns the fragment with the versioned hash of the data,
+// the algorithm is SimpleHash unless another one is set with WithHasher.
+func NewFragment(data string, opts ...Option) Fragment {
+	return Fragment{Data: data, Hash: EncodeHash(newOptions(opts).hasher, data)}
+}
+
+// check verifies the fragment with the algorithm named by its hash: the version of the prefix,
+// BinarySimpleHash for a legacy hash and the one set with WithHasher for other hashes without a prefix.
+// Returns the hash of the data in the format of the stored one, empty if the algorithm is unknown
+// or not accepted, and ErrTamperedData, wrapping the cause if the hash cannot be verified.
+func (f *Fragment) check(o options) (string, error) {
+	info, err := ParseHash(f.Hash)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrTamperedData, err)
+	}
+	if info.Name == "" && !o.legacy {
+		return "", fmt.Errorf("%w: %w: hash without a prefix", ErrTamperedData, ErrRejectedHasher)
+	}
+
+	h := o.hasher
+	switch {
+	case info.Name != "":
+		if h, err = LookupHasherVersion(info.Name, info.Version); err != nil {
+			return "", fmt.Errorf("%w: %w", ErrTamperedData, err)
+		}
+	case info.Legacy:
+		h = BinaryHasher
+	}
+	if !o.accepts(h) {
+		return "", fmt.Errorf("%w: %w: %q version %d", ErrTamperedData, ErrRejectedHasher, h.Name(), h.Version())
+	}
+
+	actual := h.Hash(f.Data)
+	if info.Name != "" {
+		actual = formatHash(h.Name(), h.Version(), actual)
+	}
+	if actual != f.Hash {
+		return actual, ErrTamperedData
+	}
+
+	return actual, nil
+}
+
+func formatHash(name string, version int, digest string) string {
+	return name + hashSeparator + "v" + strconv.Itoa(version) + hashSeparator + digest
+}
+
+// isLegacyHash reports whether the hash has the HashLen binary digits of BinarySimpleHash.
+func isLegacyHash(hash string) bool {
+	return len(hash) == HashLen && strings.Trim(hash, "01") == ""
+}
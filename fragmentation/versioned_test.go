@@ -0,0 +1,263 @@
+package fragmentation
+
+import (
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestParseHash(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		hash    string
+		expInfo HashInfo
+		expErr  error
+	}{
+		{
+			desc:    "Versioned_ShouldSucceed",
+			hash:    "sha256:v1:ba7816bf",
+			expInfo: HashInfo{Name: "sha256", Version: 1, Digest: "ba7816bf"},
+		},
+		{
+			desc:    "Legacy_ShouldSucceed",
+			hash:    "000011001100000001000001111000",
+			expInfo: HashInfo{Digest: "000011001100000001000001111000", Legacy: true},
+		},
+		{
+			desc:    "Unprefixed_ShouldSucceed",
+			hash:    "YZVJCXKGYGiDC2EL74s4rktYlZb8pD",
+			expInfo: HashInfo{Digest: "YZVJCXKGYGiDC2EL74s4rktYlZb8pD"},
+		},
+		{
+			desc:    "ShortBinary_IsNotLegacy",
+			hash:    "0101",
+			expInfo: HashInfo{Digest: "0101"},
+		},
+		{
+			desc:   "MissingDigest_ShouldFail",
+			hash:   "sha256:v1",
+			expErr: ErrMalformedHash,
+		},
+		{
+			desc:   "MissingName_ShouldFail",
+			hash:   ":v1:abc",
+			expErr: ErrMalformedHash,
+		},
+		{
+			desc:   "InvalidVersion_ShouldFail",
+			hash:   "sha256:1:abc",
+			expErr: ErrMalformedHash,
+		},
+		{
+			desc:   "ZeroVersion_ShouldFail",
+			hash:   "sha256:v0:abc",
+			expErr: ErrMalformedHash,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			info, err := ParseHash(tc.hash)
+			if tc.expErr != nil {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+			th.AssertNilError(t, err)
+			th.AssertEqualStrings(t, info.Name, tc.expInfo.Name)
+			th.AssertEqualInts(t, info.Version, tc.expInfo.Version)
+			th.AssertEqualStrings(t, info.Digest, tc.expInfo.Digest)
+			if info.Legacy != tc.expInfo.Legacy {
+				t.Errorf("expected legacy %v, got %v", tc.expInfo.Legacy, info.Legacy)
+			}
+		})
+	}
+}
+
+func TestEncodeHash(t *testing.T) {
+	th.AssertEqualStrings(t, EncodeHash(SimpleHasher, "Hello"), "simple:v1:YZVJCXKGYGiDC2EL74s4rktYlZb8pD")
+	th.AssertEqualStrings(t, EncodeHash(FNV1aHasher, "a"), "fnv1a:v1:af63dc4c8601ec8c")
+	th.AssertEqualStrings(t, NewFragment("Hello", WithHasher(FNV1aHasher)).Hash, EncodeHash(FNV1aHasher, "Hello"))
+}
+
+func TestReconstructData_VersionedHashes(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		fragments  map[int]Fragment
+		opts       []Option
+		expOut     string
+		shouldFail bool
+		expErr     error
+	}{
+		{
+			// every fragment names its own algorithm, the default one does not matter
+			desc: "MixedAlgorithms_ShouldSucceed",
+			fragments: map[int]Fragment{
+				1: NewFragment("Hello"),
+				2: NewFragment("World", WithHasher(StdlibSHA256Hasher)),
+				3: NewFragment("!", WithHasher(FNV1aHasher)),
+			},
+			opts:   []Option{WithHasher(SHA256Hasher)},
+			expOut: "HelloWorld!",
+		},
+		{
+			desc: "LegacyAndVersioned_ShouldSucceed",
+			fragments: map[int]Fragment{
+				1: {Data: "Hello", Hash: BinarySimpleHash("Hello")},
+				2: {Data: "World", Hash: SimpleHash("World")},
+				3: NewFragment("!", WithHasher(SHA256Hasher)),
+			},
+			expOut: "HelloWorld!",
+		},
+		{
+			desc: "TamperedVersioned_ShouldFail",
+			fragments: map[int]Fragment{
+				1: NewFragment("Hello"),
+				2: {Data: "Word", Hash: EncodeHash(SHA256Hasher, "World")},
+			},
+			shouldFail: true,
+			expErr:     ErrTamperedData,
+		},
+		{
+			desc: "TamperedLegacy_ShouldFail",
+			fragments: map[int]Fragment{
+				1: {Data: "Jello", Hash: BinarySimpleHash("Hello")},
+			},
+			shouldFail: true,
+			expErr:     ErrTamperedData,
+		},
+		{
+			desc: "UnknownAlgorithm_ShouldFail",
+			fragments: map[int]Fragment{
+				1: {Data: "Hello", Hash: "md5:v1:8b1a9953c4611296a827abf8c47804d7"},
+			},
+			shouldFail: true,
+			expErr:     ErrUnknownHasher,
+		},
+		{
+			desc: "UnknownVersion_ShouldFail",
+			fragments: map[int]Fragment{
+				1: {Data: "Hello", Hash: formatHash("simple", 9, SimpleHash("Hello"))},
+			},
+			shouldFail: true,
+			expErr:     ErrUnknownHasher,
+		},
+		{
+			desc: "MalformedHash_ShouldFail",
+			fragments: map[int]Fragment{
+				1: {Data: "Hello", Hash: "simple:" + SimpleHash("Hello")},
+			},
+			shouldFail: true,
+			expErr:     ErrMalformedHash,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			data, err := ReconstructData(tc.fragments, tc.opts...)
+			assertReconstruction(t, data, err, tc.expOut, tc.shouldFail, tc.expErr, nil)
+			if tc.shouldFail {
+				th.AssertCorrectError(t, err, ErrTamperedData)
+			}
+		})
+	}
+}
+
+func TestVerifyFragments_VersionedHashes(t *testing.T) {
+	fragments := map[int]Fragment{
+		1: {Data: "Jello", Hash: EncodeHash(SHA256Hasher, "Hello")},
+		2: {Data: "World", Hash: BinarySimpleHash("World")},
+		3: {Data: "!", Hash: "md5:v1:9033e0e305f247c0c3c80d0c7848c8b3"},
+	}
+
+	report, err := VerifyFragments(fragments, 3)
+	th.AssertCorrectError(t, err, ErrTamperedData)
+	th.AssertEqualIntSlices(t, report.Valid, []int{2})
+	th.AssertEqualIntSlices(t, tamperedSequences(report.Tampered), []int{1, 3})
+	th.AssertEqualStrings(t, report.Tampered[0].Actual, EncodeHash(SHA256Hasher, "Jello"))
+	th.AssertEqualStrings(t, report.Tampered[1].Actual, "")
+}
+
+func TestReconstructData_AcceptedHashers(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		fragments  map[int]Fragment
+		opts       []Option
+		expOut     string
+		shouldFail bool
+		expErr     error
+	}{
+		{
+			desc:      "AcceptedAlgorithm_ShouldSucceed",
+			fragments: map[int]Fragment{1: NewFragment("Hello", WithHasher(StdlibSHA256Hasher))},
+			opts:      []Option{WithAcceptedHashers(StdlibSHA256Hasher, SHA256Hasher)},
+			expOut:    "Hello",
+		},
+		{
+			desc:       "VersionedWeakAlgorithm_ShouldFail",
+			fragments:  map[int]Fragment{1: {Data: "evil", Hash: EncodeHash(BinaryHasher, "evil")}},
+			opts:       []Option{WithHasher(StdlibSHA256Hasher), WithAcceptedHashers(StdlibSHA256Hasher)},
+			shouldFail: true,
+			expErr:     ErrRejectedHasher,
+		},
+		{
+			desc:       "LegacyHash_NotAccepted_ShouldFail",
+			fragments:  map[int]Fragment{1: {Data: "evil", Hash: BinarySimpleHash("evil")}},
+			opts:       []Option{WithAcceptedHashers(StdlibSHA256Hasher)},
+			shouldFail: true,
+			expErr:     ErrRejectedHasher,
+		},
+		{
+			desc:      "LegacyHash_Accepted_ShouldSucceed",
+			fragments: map[int]Fragment{1: {Data: "Hello", Hash: BinarySimpleHash("Hello")}},
+			opts:      []Option{WithAcceptedHashers(BinaryHasher)},
+			expOut:    "Hello",
+		},
+		{
+			desc:       "UnprefixedHash_NotAcceptedFallback_ShouldFail",
+			fragments:  map[int]Fragment{1: {Data: "Hello", Hash: SimpleHash("Hello")}},
+			opts:       []Option{WithAcceptedHashers(StdlibSHA256Hasher)},
+			shouldFail: true,
+			expErr:     ErrRejectedHasher,
+		},
+		{
+			desc:       "WithoutLegacy_LegacyHash_ShouldFail",
+			fragments:  map[int]Fragment{1: {Data: "evil", Hash: BinarySimpleHash("evil")}},
+			opts:       []Option{WithoutLegacy()},
+			shouldFail: true,
+			expErr:     ErrRejectedHasher,
+		},
+		{
+			desc:       "WithoutLegacy_UnprefixedHash_ShouldFail",
+			fragments:  map[int]Fragment{1: {Data: "Hello", Hash: SimpleHash("Hello")}},
+			opts:       []Option{WithoutLegacy()},
+			shouldFail: true,
+			expErr:     ErrRejectedHasher,
+		},
+		{
+			desc:      "WithoutLegacy_VersionedHash_ShouldSucceed",
+			fragments: map[int]Fragment{1: NewFragment("Hello")},
+			opts:      []Option{WithoutLegacy()},
+			expOut:    "Hello",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			data, err := ReconstructData(tc.fragments, tc.opts...)
+			assertReconstruction(t, data, err, tc.expOut, tc.shouldFail, tc.expErr, nil)
+			if tc.shouldFail {
+				th.AssertCorrectError(t, err, ErrTamperedData)
+			}
+		})
+	}
+}
+
+func TestVerifyFragments_AcceptedHashers(t *testing.T) {
+	fragments := map[int]Fragment{
+		1: NewFragment("Hello", WithHasher(StdlibSHA256Hasher)),
+		2: {Data: "World", Hash: BinarySimpleHash("World")},
+	}
+
+	report, err := VerifyFragments(fragments, 2, WithAcceptedHashers(StdlibSHA256Hasher))
+	th.AssertCorrectError(t, err, ErrTamperedData)
+	th.AssertEqualIntSlices(t, report.Valid, []int{1})
+	th.AssertEqualIntSlices(t, tamperedSequences(report.Tampered), []int{2})
+	th.AssertEqualStrings(t, report.Tampered[0].Actual, "")
+}
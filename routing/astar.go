@@ -0,0 +1,175 @@
+package routing
+
+import (
+	"fmt"
+	"math"
+)
+
+// Heuristic estimates the latency from a router to the target for the A* search.
+// The estimate must never exceed the minimum latency without compression (admissible),
+// +Inf can be returned when the target is known to be unreachable.
+type Heuristic interface {
+	Estimate(from, target string) float32
+}
+
+// HeuristicFunc adapts an ordinary function to the Heuristic interface.
+type HeuristicFunc func(from, target string) float32
+
+// Estimate calls f(from, target).
+func (f HeuristicFunc) Estimate(from, target string) float32 { return f(from, target) }
+
+// FindMinimumLatencyPathAStar computes the same route as FindMinimumLatencyPath using the A* search.
+// The heuristic guides the search towards the target, so fewer routers are explored.
+// Compression can be applied once per path at any of the compressionNodes, the heuristic is
+// scaled down while the compression is not used, so it stays admissible.
+// Built with -tags routingdebug the heuristic is checked against the real latencies first.
+//
+// Parameters:
+//   - graph: adjacency list representing the graph
+//   - compressionNodes: list of node identifiers that support compression
+//   - source: id of the starting node
+//   - target: id of the destination node
+//   - heuristic: lower bound of the latency from a router to the target
+//
+// Returns:
+//   - route: hops from source to target, formatted as A->B*->C where '*' marks the compression node
+//   - dist: the total latency of the path
+//   - err: ErrUnknownNode, ErrInvalidLatency, ErrNoPath or ErrInadmissibleHeuristic in debug mode
+func FindMinimumLatencyPathAStar(
+	graph map[string][]Node,
+	compressionNodes []string,
+	source, target string,
+	heuristic Heuristic,
+) (route Route, dist float32, err error) {
+	g, err := NewGraph(graph)
+	if err != nil {
+		return Route{}, 0, err
+	}
+
+	return g.FindMinimumLatencyPathAStar(compressionNodes, source, target, heuristic)
+}
+
+// FindMinimumLatencyPathAStar is the Graph counterpart of the package level FindMinimumLatencyPathAStar.
+func (g *Graph) FindMinimumLatencyPathAStar(
+	compressionNodes []string,
+	source, target string,
+	heuristic Heuristic,
+) (Route, float32, error) {
+	s, t, err := g.lookup(source, target)
+	if err != nil {
+		return Route{}, 0, err
+	}
+
+	factors := g.defaultCompressionFactors(compressionNodes)
+
+	if debug {
+		if err := g.validateHeuristic(heuristic, t); err != nil {
+			return Route{}, 0, err
+		}
+	}
+
+	search := newSearch(g, factors, 1)
+	search.useHeuristic(func(node int) float32 {
+		return heuristic.Estimate(g.ID(node), target)
+	})
+	search.seed(state{node: s}, 0)
+
+	end, found := search.run(t)
+	if !found {
+		return Route{}, 0, fmt.Errorf("%w: from %q to %q", ErrNoPath, source, target)
+	}
+
+	return search.route(end), search.distance(end), nil
+}
+
+// validateHeuristic compares the estimate of every router with its real latency
+// to the target without compression and reports the first overestimate.
+func (g *Graph) validateHeuristic(heuristic Heuristic, target int) error {
+	// distances to the target are the distances from the target in the reversed graph
+	distances := g.reverse().distancesFrom(target)
+
+	for u, distance := range distances {
+		estimate := heuristic.Estimate(g.ID(u), g.ID(target))
+		if math.IsInf(float64(distance), 1) {
+			// any estimate is admissible for a router which cannot reach the target
+			continue
+		}
+
+		// allow for the rounding of float32 sums
+		if estimate > distance+distance*1e-5 {
+			return fmt.Errorf("%w: estimate %v > latency %v from %q to %q",
+				ErrInadmissibleHeuristic, estimate, distance, g.ID(u), g.ID(target))
+		}
+	}
+
+	return nil
+}
+
+// LandmarkHeuristic is the ALT (A*, landmarks, triangle inequality) heuristic.
+// It precomputes the latencies from and to a handful of landmark routers, then for
+// any landmark L the triangle inequality gives two lower bounds of the latency d(v, t):
+//
+//	d(v, t) >= d(v, L) - d(t, L)
+//	d(v, t) >= d(L, t) - d(L, v)
+//
+// Landmarks at the edge of the network, behind the routers usually queried, give the best bounds.
+type LandmarkHeuristic struct {
+	graph *Graph
+	from  [][]float32 // from[i][v] is the latency from landmark i to router v
+	to    [][]float32 // to[i][v] is the latency from router v to landmark i
+}
+
+// NewLandmarkHeuristic precomputes the latencies between the landmarks and every router of the graph.
+// It runs two full Dijkstra searches per landmark. Returns ErrUnknownNode for unknown landmarks.
+func NewLandmarkHeuristic(g *Graph, landmarks []string) (*LandmarkHeuristic, error) {
+	h := &LandmarkHeuristic{
+		graph: g,
+		from:  make([][]float32, len(landmarks)),
+		to:    make([][]float32, len(landmarks)),
+	}
+
+	reversed := g.reverse()
+	for i, id := range landmarks {
+		l, ok := g.Index(id)
+		if !ok {
+			return nil, fmt.Errorf("%w: landmark %q", ErrUnknownNode, id)
+		}
+
+		h.from[i] = g.distancesFrom(l)
+		h.to[i] = reversed.distancesFrom(l)
+	}
+
+	return h, nil
+}
+
+// Estimate returns the best lower bound of the latency from router "from" to target over all landmarks.
+// Returns +Inf when the landmarks prove the target is not reachable and 0 for unknown routers.
+func (h *LandmarkHeuristic) Estimate(from, target string) float32 {
+	v, okFrom := h.graph.Index(from)
+	t, okTarget := h.graph.Index(target)
+	if !okFrom || !okTarget {
+		return 0
+	}
+
+	inf := float32(math.Inf(1))
+	var estimate float32
+	for i := range h.from {
+		fromL, targetFromL := h.from[i][v], h.from[i][t]
+		toL, targetToL := h.to[i][v], h.to[i][t]
+
+		// If L reaches v but not t, v cannot reach t either (it would be reachable through v).
+		// If t reaches L but v does not, v cannot reach t either.
+		if fromL != inf && targetFromL == inf || targetToL != inf && toL == inf {
+			return inf
+		}
+
+		if toL != inf && targetToL != inf {
+			estimate = max(estimate, toL-targetToL)
+		}
+		if fromL != inf && targetFromL != inf {
+			estimate = max(estimate, targetFromL-fromL)
+		}
+	}
+
+	return estimate
+}
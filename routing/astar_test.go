@@ -0,0 +1,220 @@
+package routing
+
+import (
+	"math"
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestFindMinimumLatencyPathAStar(t *testing.T) {
+	graph := map[string][]Node{
+		"A": {{"B", 10}, {"C", 20}},
+		"B": {{"D", 15}},
+		"C": {{"D", 30}},
+		"D": {},
+	}
+	zero := HeuristicFunc(func(from, target string) float32 { return 0 })
+	// exact latencies to D without compression
+	exact := HeuristicFunc(func(from, target string) float32 {
+		return map[string]float32{"A": 25, "B": 15, "C": 30, "D": 0}[from]
+	})
+
+	testCases := []struct {
+		desc             string
+		compressionNodes []string
+		heuristic        Heuristic
+		expPath          string
+		expLatency       float32
+	}{
+		{
+			desc:       "ZeroHeuristic_WithoutCompression",
+			heuristic:  zero,
+			expPath:    "A->B->D",
+			expLatency: 25,
+		},
+		{
+			desc:             "ZeroHeuristic_WithCompression",
+			compressionNodes: []string{"B", "C"},
+			heuristic:        zero,
+			expPath:          "A->B*->D",
+			expLatency:       17.5,
+		},
+		{
+			desc:       "ExactHeuristic_WithoutCompression",
+			heuristic:  exact,
+			expPath:    "A->B->D",
+			expLatency: 25,
+		},
+		{
+			desc:             "ExactHeuristic_WithCompression",
+			compressionNodes: []string{"B", "C"},
+			heuristic:        exact,
+			expPath:          "A->B*->D",
+			expLatency:       17.5,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			route, latency, err := FindMinimumLatencyPathAStar(graph, tc.compressionNodes, "A", "D", tc.heuristic)
+			th.AssertNilError(t, err)
+			th.AssertEqualStrings(t, route.String(), tc.expPath)
+			th.AssertEqualFloats(t, latency, tc.expLatency)
+		})
+	}
+}
+
+func TestFindMinimumLatencyPathAStar_WithLandmarks_ShouldMatchDijkstra(t *testing.T) {
+	adjacency, compressionNodes := randomGraph(2_000, 3)
+	g, err := NewGraph(adjacency)
+	th.AssertNilError(t, err)
+
+	h, err := NewLandmarkHeuristic(g, []string{routerId(0), routerId(500), routerId(1_000), routerId(1_500)})
+	th.AssertNilError(t, err)
+
+	for _, target := range []int{1, 250, 999, 1_234, 1_999} {
+		th.AssertNilError(t, g.validateHeuristic(h, target))
+
+		_, expLatency, err := g.FindMinimumLatencyPath(compressionNodes, routerId(7), routerId(target))
+		th.AssertNilError(t, err)
+
+		route, latency, err := g.FindMinimumLatencyPathAStar(compressionNodes, routerId(7), routerId(target), h)
+		th.AssertNilError(t, err)
+		th.AssertEqualFloats(t, latency, expLatency)
+		th.AssertEqualFloats(t, route.Latency(), expLatency)
+	}
+}
+
+func TestValidateHeuristic(t *testing.T) {
+	g, err := NewGraph(map[string][]Node{
+		"A": {{"B", 10}},
+		"B": {{"C", 10}},
+		"C": {},
+		"D": {{"A", 1}},
+	})
+	th.AssertNilError(t, err)
+	target, _ := g.Index("C")
+
+	testCases := []struct {
+		desc      string
+		heuristic Heuristic
+		expErr    error
+	}{
+		{
+			desc: "Admissible_ShouldSucceed",
+			heuristic: HeuristicFunc(func(from, target string) float32 {
+				return map[string]float32{"A": 20, "B": 5, "D": 21}[from]
+			}),
+		},
+		{
+			desc: "Inadmissible_ShouldFailWith_ErrInadmissibleHeuristic",
+			heuristic: HeuristicFunc(func(from, target string) float32 {
+				return map[string]float32{"A": 20, "B": 11}[from]
+			}),
+			expErr: ErrInadmissibleHeuristic,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := g.validateHeuristic(tc.heuristic, target)
+			if tc.expErr != nil {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+			th.AssertNilError(t, err)
+		})
+	}
+}
+
+func TestLandmarkHeuristic(t *testing.T) {
+	// A -> B -> C -> D, E is isolated
+	g, err := NewGraph(map[string][]Node{
+		"A": {{"B", 1}},
+		"B": {{"C", 2}},
+		"C": {{"D", 3}},
+		"D": {},
+		"E": {},
+	})
+	th.AssertNilError(t, err)
+
+	h, err := NewLandmarkHeuristic(g, []string{"A", "D"})
+	th.AssertNilError(t, err)
+
+	testCases := []struct {
+		desc   string
+		from   string
+		target string
+		expOut float32
+	}{
+		{
+			desc:   "Exact_OnLandmarkLine",
+			from:   "A",
+			target: "D",
+			expOut: 6,
+		},
+		{
+			desc:   "Exact_BetweenLandmarks",
+			from:   "B",
+			target: "C",
+			expOut: 2,
+		},
+		{
+			desc:   "Unreachable_ShouldReturn_Inf",
+			from:   "D",
+			target: "A",
+			expOut: float32(math.Inf(1)),
+		},
+		{
+			desc:   "UnknownRouter_ShouldReturn_Zero",
+			from:   "X",
+			target: "A",
+			expOut: 0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			th.AssertEqualFloats(t, h.Estimate(tc.from, tc.target), tc.expOut)
+		})
+	}
+
+	_, err = NewLandmarkHeuristic(g, []string{"X"})
+	th.AssertCorrectError(t, err, ErrUnknownNode)
+}
+
+func BenchmarkFindMinimumLatencyPathAStar(b *testing.B) {
+	adjacency, compressionNodes := randomGraph(100_000, 4)
+	g, err := NewGraph(adjacency)
+	if err != nil {
+		b.Fatal(err)
+	}
+	target := routerId(50_000)
+
+	h, err := NewLandmarkHeuristic(g, []string{routerId(0), routerId(25_000), routerId(75_000), target})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc      string
+		heuristic Heuristic
+	}{
+		{
+			desc:      "ZeroHeuristic",
+			heuristic: HeuristicFunc(func(from, target string) float32 { return 0 }),
+		},
+		{
+			desc:      "LandmarkHeuristic",
+			heuristic: h,
+		},
+	}
+
+	for _, tc := range testCases {
+		b.Run(tc.desc, func(b *testing.B) {
+			for b.Loop() {
+				if _, _, err := g.FindMinimumLatencyPathAStar(compressionNodes, routerId(1), target, tc.heuristic); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
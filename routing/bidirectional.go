@@ -0,0 +1,129 @@
+package routing
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// meeting is the best connection found so far between the forward and the backward search.
+type meeting struct {
+	state   state
+	latency float32 // latency of the best path through state, +Inf if none
+}
+
+// update records the path through st if it is faster than the best one so far.
+func (m *meeting) update(st state, latency float32) {
+	if latency < m.latency {
+		m.state, m.latency = st, latency
+	}
+}
+
+// FindMinimumLatencyPathBidirectional computes the same route as FindMinimumLatencyPath
+// using bidirectional Dijkstra: one search runs from the source, the other one from the target
+// in the reversed graph, until they meet in the middle. On large sparse graphs both searches
+// together explore far fewer routers than a single one.
+//
+// Parameters:
+//   - graph: adjacency list representing the graph
+//   - compressionNodes: list of node identifiers that support compression
+//   - source: id of the starting node
+//   - target: id of the destination node
+//
+// Returns:
+//   - route: hops from source to target, formatted as A->B*->C where '*' marks the compression node
+//   - dist: the total latency of the path
+//   - err: ErrUnknownNode, ErrInvalidLatency or ErrNoPath if the path cannot be computed
+func FindMinimumLatencyPathBidirectional(graph map[string][]Node, compressionNodes []string, source, target string) (route Route, dist float32, err error) {
+	g, err := NewGraph(graph)
+	if err != nil {
+		return Route{}, 0, err
+	}
+
+	return g.FindMinimumLatencyPathBidirectional(compressionNodes, source, target)
+}
+
+// FindMinimumLatencyPathBidirectional is the Graph counterpart of the package level FindMinimumLatencyPathBidirectional.
+// The reversed graph is built on every call.
+func (g *Graph) FindMinimumLatencyPathBidirectional(compressionNodes []string, source, target string) (Route, float32, error) {
+	s, t, err := g.lookup(source, target)
+	if err != nil {
+		return Route{}, 0, err
+	}
+
+	factors := g.defaultCompressionFactors(compressionNodes)
+
+	forward := newSearch(g, factors, 1)
+	backward := newSearch(g.reverse(), factors, 1)
+	backward.backward = true
+
+	m := &meeting{latency: float32(math.Inf(1))}
+	forward.opposite, forward.meeting = backward, m
+	backward.opposite, backward.meeting = forward, m
+
+	// The states of both searches are the same (router, compressions used) pairs,
+	// backward the distance of a state is the latency from it to the target.
+	// The target can be reached with any number of compressions.
+	forward.seed(state{node: s}, 0)
+	for used := range backward.layers {
+		backward.seed(state{t, used}, 0)
+	}
+	m.update(state{node: s}, backward.distance(state{node: s}))
+
+	// Stop when no path through the unexplored states can be faster than the best meeting:
+	// any such path has at least the latency of the nearest state of each search.
+	for {
+		topForward, okForward := forward.top()
+		topBackward, okBackward := backward.top()
+		if !okForward || !okBackward || topForward+topBackward >= m.latency {
+			break
+		}
+
+		// expand the search with the nearer state
+		search := forward
+		if topBackward < topForward {
+			search = backward
+		}
+		router, _ := search.next()
+		search.expand(router)
+	}
+
+	if math.IsInf(float64(m.latency), 1) {
+		return Route{}, 0, fmt.Errorf("%w: from %q to %q", ErrNoPath, source, target)
+	}
+
+	// m.latency adds the two halves in another order than FindMinimumLatencyPath,
+	// the sum along the route from the source is the same float32
+	route := bidirectionalRoute(forward, backward, m)
+
+	return route, route.Latency(), nil
+}
+
+// bidirectionalRoute joins the path from the source to the meeting state
+// with the path from the meeting state to the target.
+func bidirectionalRoute(forward, backward *search, m *meeting) Route {
+	// the forward traces lead to the source, the backward ones to the target
+	backwardPath := traceBack(forward.prev, m.state)
+	if len(backwardPath) == 0 {
+		backwardPath = []state{m.state}
+	}
+	toTarget := traceBack(backward.prev, m.state)
+	if len(toTarget) > 0 {
+		slices.Reverse(toTarget)
+		backwardPath = append(toTarget[:len(toTarget)-1], backwardPath...)
+	}
+
+	// up to the meeting state the forward search reached next with the link,
+	// after it the backward search reached prev with the reversed link
+	forwardSide := make(map[state]bool, len(backwardPath))
+	for _, st := range backwardPath[max(len(toTarget)-1, 0):] {
+		forwardSide[st] = true
+	}
+
+	return newRoute(forward.graph, forward.factors, backwardPath, func(prev, next state) float32 {
+		if forwardSide[next] {
+			return forward.linkLatency(next)
+		}
+		return backward.linkLatency(prev)
+	})
+}
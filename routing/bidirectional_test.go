@@ -0,0 +1,164 @@
+package routing
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestFindMinimumLatencyPathBidirectional(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		graph            map[string][]Node
+		compressionNodes []string
+		source           string
+		target           string
+		expPath          string
+		expLatency       float32
+		expErr           error
+	}{
+		{
+			desc: "Success_WithoutCompression",
+			graph: map[string][]Node{
+				"A": {{"B", 10}, {"C", 20}},
+				"B": {{"D", 15}},
+				"C": {{"D", 30}},
+				"D": {},
+			},
+			source:     "A",
+			target:     "D",
+			expPath:    "A->B->D",
+			expLatency: 25,
+		},
+		{
+			desc: "Success_WithCompression",
+			graph: map[string][]Node{
+				"A": {{"B", 10}, {"C", 20}},
+				"B": {{"D", 15}},
+				"C": {{"D", 30}},
+				"D": {},
+			},
+			compressionNodes: []string{"B", "C"},
+			source:           "A",
+			target:           "D",
+			expPath:          "A->B*->D",
+			expLatency:       17.5,
+		},
+		{
+			desc: "Success_LongPath_WithCompressionAtSource",
+			graph: map[string][]Node{
+				"A": {{"B", 4}, {"C", 8}},
+				"B": {{"E", 6}, {"F", 1}},
+				"C": {{"D", 2}},
+				"D": {{"E", 10}},
+				"E": {},
+				"F": {{"D", 1}},
+			},
+			compressionNodes: []string{"A", "B"},
+			source:           "A",
+			target:           "D",
+			expPath:          "A*->B->F->D",
+			expLatency:       4,
+		},
+		{
+			desc: "SourceIsTarget_ShouldReturn_EmptyRoute",
+			graph: map[string][]Node{
+				"A": {{"B", 10}},
+			},
+			source:     "A",
+			target:     "A",
+			expPath:    "",
+			expLatency: 0,
+		},
+		{
+			desc: "Unreachable_ShouldFailWith_ErrNoPath",
+			graph: map[string][]Node{
+				"A": {{"B", 10}},
+				"C": {},
+			},
+			source: "A",
+			target: "C",
+			expErr: ErrNoPath,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			route, latency, err := FindMinimumLatencyPathBidirectional(tc.graph, tc.compressionNodes, tc.source, tc.target)
+			if tc.expErr != nil {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+
+			th.AssertNilError(t, err)
+			th.AssertEqualStrings(t, route.String(), tc.expPath)
+			th.AssertEqualFloats(t, latency, tc.expLatency)
+			th.AssertEqualFloats(t, route.Latency(), tc.expLatency)
+		})
+	}
+}
+
+func TestFindMinimumLatencyPathBidirectional_ShouldMatchDijkstra(t *testing.T) {
+	adjacency, compressionNodes := randomGraph(2_000, 3)
+	g, err := NewGraph(adjacency)
+	th.AssertNilError(t, err)
+
+	for _, pair := range [][2]int{{0, 1}, {7, 1_000}, {1_999, 3}, {500, 1_500}, {42, 42}} {
+		assertMatchesDijkstra(t, g, compressionNodes, routerId(pair[0]), routerId(pair[1]))
+	}
+}
+
+// The searches add the latencies in different orders, with fractions the sums can differ in the last bit.
+func TestFindMinimumLatencyPathBidirectional_NonIntegerLatencies_ShouldMatchDijkstra(t *testing.T) {
+	for seed := range uint64(100) {
+		rnd := rand.New(rand.NewPCG(seed, 3))
+		adjacency, compressionNodes := randomWeightedGraph(rnd, 200, 3, func() float32 { return rnd.Float32() * 10 })
+		g, err := NewGraph(adjacency)
+		th.AssertNilError(t, err)
+
+		assertMatchesDijkstra(t, g, compressionNodes, routerId(rnd.IntN(200)), routerId(rnd.IntN(200)))
+	}
+}
+
+func assertMatchesDijkstra(t *testing.T, g *Graph, compressionNodes []string, source, target string) {
+	t.Helper()
+	expRoute, expLatency, err := g.FindMinimumLatencyPath(compressionNodes, source, target)
+	th.AssertNilError(t, err)
+
+	route, latency, err := g.FindMinimumLatencyPathBidirectional(compressionNodes, source, target)
+	th.AssertNilError(t, err)
+	th.AssertEqualFloats(t, latency, expLatency)
+	th.AssertEqualFloats(t, route.Latency(), expLatency)
+	th.AssertEqualInts(t, len(route.CompressedAt()), len(expRoute.CompressedAt()))
+
+	// the route must be connected from source to target
+	nodes := route.Nodes()
+	if len(nodes) > 0 {
+		th.AssertEqualStrings(t, nodes[0], source)
+		th.AssertEqualStrings(t, nodes[len(nodes)-1], target)
+	}
+}
+
+func BenchmarkFindMinimumLatencyPathBidirectional(b *testing.B) {
+	adjacency, compressionNodes := randomGraph(100_000, 4)
+	g, err := NewGraph(adjacency)
+	if err != nil {
+		b.Fatal(err)
+	}
+	target := routerId(50_000)
+
+	b.Run("Unidirectional", func(b *testing.B) {
+		for b.Loop() {
+			if _, _, err := g.FindMinimumLatencyPath(compressionNodes, routerId(1), target); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Bidirectional", func(b *testing.B) {
+		for b.Loop() {
+			if _, _, err := g.FindMinimumLatencyPathBidirectional(compressionNodes, routerId(1), target); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
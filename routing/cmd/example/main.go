@@ -14,7 +14,11 @@ func main() {
 	graph["C"] = []r.Node{{Id: "D", Latency: 30}}
 	graph["D"] = []r.Node{}
 
-	path, latency := r.FindMinimumLatencyPath(graph, []string{}, "A", "D")
+	route, latency, err := r.FindMinimumLatencyPath(graph, []string{"B", "C"}, "A", "D")
+	if err != nil {
+		fmt.Printf("error: %v\n", err)
+		return
+	}
 
-	fmt.Printf("path: %v, latency: %v\n", path, latency)
+	fmt.Printf("path: %v, latency: %v\n", route, latency)
 }
@@ -0,0 +1,20 @@
+//go:build routingdebug
+
+package routing
+
+import (
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestFindMinimumLatencyPathAStar_InadmissibleHeuristic(t *testing.T) {
+	graph := map[string][]Node{
+		"A": {{"B", 10}},
+		"B": {},
+	}
+	overestimate := HeuristicFunc(func(from, target string) float32 { return 100 })
+
+	_, _, err := FindMinimumLatencyPathAStar(graph, nil, "A", "B", overestimate)
+	th.AssertCorrectError(t, err, ErrInadmissibleHeuristic)
+}
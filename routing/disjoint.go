@@ -0,0 +1,216 @@
+package routing
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// Disjointness defines what the two routes of a DisjointPair must not share.
+type Disjointness int
+
+const (
+	// EdgeDisjoint routes share no link.
+	EdgeDisjoint Disjointness = iota
+	// NodeDisjoint routes share no link and no router except source and target.
+	NodeDisjoint
+)
+
+// DisjointPair holds two disjoint routes between the same routers,
+// First is the one with the lower latency.
+type DisjointPair struct {
+	First  Route
+	Second Route
+}
+
+// Latency returns the total latency of both routes.
+func (p DisjointPair) Latency() float32 {
+	return p.First.Latency() + p.Second.Latency()
+}
+
+// FindDisjointPaths finds the pair of disjoint routes from source to target with the minimum
+// total latency, using Suurballe's algorithm. Compression is not taken into account.
+//
+// Parameters:
+//   - graph: adjacency list representing the graph
+//   - source: id of the starting node
+//   - target: id of the destination node
+//   - disjointness: whether the routes must not share links (EdgeDisjoint) or routers (NodeDisjoint)
+//
+// Returns:
+//   - pair: the two routes, both empty if source is the target
+//   - err: ErrUnknownNode, ErrInvalidLatency, ErrNoPath if the target is not reachable at all
+//     or ErrNoDisjointPaths if there is only a single route
+func FindDisjointPaths(graph map[string][]Node, source, target string, disjointness Disjointness) (DisjointPair, error) {
+	g, err := NewGraph(graph)
+	if err != nil {
+		return DisjointPair{}, err
+	}
+
+	return g.FindDisjointPaths(source, target, disjointness)
+}
+
+// FindDisjointPaths is the Graph counterpart of the package level FindDisjointPaths.
+func (g *Graph) FindDisjointPaths(source, target string, disjointness Disjointness) (DisjointPair, error) {
+	s, t, err := g.lookup(source, target)
+	if err != nil {
+		return DisjointPair{}, err
+	}
+
+	if s == t {
+		return DisjointPair{}, nil
+	}
+
+	// Two disjoint paths with minimum total latency are a min-cost flow of 2 units,
+	// where every link (and router for NodeDisjoint) has capacity 1.
+	// Each augmentation is a Dijkstra run on the residual network with reduced costs (Suurballe),
+	// the second path may cancel links of the first one by using their reverse arcs.
+	network, start, end := newFlowNetwork(g, s, t, disjointness)
+	potentials := make([]float32, len(network.out))
+	for i := range 2 {
+		if !network.augment(start, end, potentials) {
+			if i == 0 {
+				return DisjointPair{}, fmt.Errorf("%w: from %q to %q", ErrNoPath, g.ID(s), g.ID(t))
+			}
+			return DisjointPair{}, fmt.Errorf("%w: from %q to %q", ErrNoDisjointPaths, g.ID(s), g.ID(t))
+		}
+	}
+
+	factors := g.noCompressionFactors()
+	first := g.pathRoute(factors, g.newPath(factors, s, network.takePath(start, end)))
+	second := g.pathRoute(factors, g.newPath(factors, s, network.takePath(start, end)))
+	if second.Latency() < first.Latency() {
+		first, second = second, first
+	}
+
+	return DisjointPair{First: first, Second: second}, nil
+}
+
+// flowNetwork is the residual network used to find disjoint paths.
+// Every arc is stored together with its reverse arc, the reverse of arc a is a^1.
+type flowNetwork struct {
+	out  [][]int   // arcs leaving each node
+	to   []int     // node the arc leads to
+	cap  []int     // remaining capacity
+	cost []float32 // latency of the arc, negative for the reverse arcs
+	link []int     // link of the Graph the arc represents, -1 for the arcs splitting routers
+}
+
+// newFlowNetwork creates the network for the Graph and returns it with its source and sink nodes.
+// For NodeDisjoint every router u is split to u_in (2u) and u_out (2u+1) connected with an arc
+// of capacity 1, so at most one path can pass through it.
+func newFlowNetwork(g *Graph, s, t int, disjointness Disjointness) (*flowNetwork, int, int) {
+	in, out := func(u int) int { return u }, func(u int) int { return u }
+	nodes := g.Len()
+	if disjointness == NodeDisjoint {
+		in, out = func(u int) int { return 2 * u }, func(u int) int { return 2*u + 1 }
+		nodes *= 2
+	}
+
+	n := &flowNetwork{out: make([][]int, nodes)}
+	if disjointness == NodeDisjoint {
+		for u := range g.Len() {
+			n.addArc(in(u), out(u), 0, -1)
+		}
+	}
+
+	for u := range g.Len() {
+		from, to := g.links(u)
+		for l := from; l < to; l++ {
+			n.addArc(out(u), in(int(g.targets[l])), g.latencies[l], l)
+		}
+	}
+
+	// the paths start after the source and end before the target are split
+	return n, out(s), in(t)
+}
+
+// addArc adds the arc with capacity 1 and its reverse arc with no capacity.
+func (n *flowNetwork) addArc(from, to int, cost float32, link int) {
+	n.out[from] = append(n.out[from], len(n.to))
+	n.to, n.cap, n.cost, n.link = append(n.to, to), append(n.cap, 1), append(n.cost, cost), append(n.link, link)
+
+	n.out[to] = append(n.out[to], len(n.to))
+	n.to, n.cap, n.cost, n.link = append(n.to, from), append(n.cap, 0), append(n.cost, -cost), append(n.link, link)
+}
+
+// augment sends one unit of flow along the shortest path from source to sink in the residual network.
+// The reduced costs cost(u,v) + potential(u) - potential(v) are non-negative, so Dijkstra can be used
+// even with the negative reverse arcs. Returns false if the sink is not reachable.
+func (n *flowNetwork) augment(source, sink int, potentials []float32) bool {
+	distances := make([]float32, len(n.out))
+	traces := make([]int, len(n.out)) // arc used to reach each node
+	settled := make([]bool, len(n.out))
+	for i := range distances {
+		distances[i] = float32(math.Inf(1))
+		traces[i] = -1
+	}
+
+	distances[source] = 0
+	minHeap := &minHeap{}
+	heap.Push(minHeap, neighbor{state{node: source}, 0})
+
+	for minHeap.Len() > 0 {
+		u := heap.Pop(minHeap).(neighbor).state.node
+		if settled[u] {
+			continue
+		}
+		settled[u] = true
+
+		for _, a := range n.out[u] {
+			v := n.to[a]
+			if n.cap[a] == 0 || settled[v] {
+				continue
+			}
+
+			// rounding can make the reduced cost of the arcs on a shortest path slightly negative
+			reduced := max(n.cost[a]+potentials[u]-potentials[v], 0)
+			if distances[u]+reduced < distances[v] {
+				distances[v] = distances[u] + reduced
+				traces[v] = a
+				heap.Push(minHeap, neighbor{state{node: v}, distances[v]})
+			}
+		}
+	}
+
+	if !settled[sink] {
+		return false
+	}
+
+	// keep the reduced costs non-negative for the next run
+	for u := range potentials {
+		if settled[u] {
+			potentials[u] += distances[u]
+		} else {
+			potentials[u] += distances[sink]
+		}
+	}
+
+	for v := sink; v != source; v = n.to[traces[v]^1] {
+		n.cap[traces[v]]--
+		n.cap[traces[v]^1]++
+	}
+
+	return true
+}
+
+// takePath follows the arcs with flow from source to sink, removing the flow on the way,
+// and returns the links of the Graph along the path.
+func (n *flowNetwork) takePath(source, sink int) []int {
+	links := []int{}
+	for u := source; u != sink; {
+		for _, a := range n.out[u] {
+			// forward arcs are stored at even indices, they carry flow when the capacity is used
+			if a%2 == 0 && n.cap[a] == 0 {
+				n.cap[a] = 1
+				if n.link[a] >= 0 {
+					links = append(links, n.link[a])
+				}
+				u = n.to[a]
+				break
+			}
+		}
+	}
+
+	return links
+}
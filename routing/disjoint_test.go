@@ -0,0 +1,139 @@
+package routing
+
+import (
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestFindDisjointPaths(t *testing.T) {
+	// the shortest path S->A->B->T blocks every second path,
+	// the optimal pair does not contain it
+	trapGraph := map[string][]Node{
+		"S": {{"A", 1}, {"B", 2}},
+		"A": {{"B", 1}, {"T", 2}},
+		"B": {{"T", 1}},
+		"T": {},
+	}
+	// every short path passes through M
+	sharedRouterGraph := map[string][]Node{
+		"S": {{"A", 1}, {"M", 1}, {"C", 5}},
+		"A": {{"M", 1}},
+		"M": {{"T", 1}, {"B", 1}},
+		"B": {{"T", 1}},
+		"C": {{"T", 5}},
+		"T": {},
+	}
+
+	testCases := []struct {
+		desc         string
+		graph        map[string][]Node
+		source       string
+		target       string
+		disjointness Disjointness
+		expFirst     string
+		expSecond    string
+		expLatency   float32
+		expErr       error
+	}{
+		{
+			desc:         "TrapGraph_EdgeDisjoint",
+			graph:        trapGraph,
+			source:       "S",
+			target:       "T",
+			disjointness: EdgeDisjoint,
+			expFirst:     "S->A->T",
+			expSecond:    "S->B->T",
+			expLatency:   6,
+		},
+		{
+			desc:         "TrapGraph_NodeDisjoint",
+			graph:        trapGraph,
+			source:       "S",
+			target:       "T",
+			disjointness: NodeDisjoint,
+			expFirst:     "S->A->T",
+			expSecond:    "S->B->T",
+			expLatency:   6,
+		},
+		{
+			desc:         "SharedRouter_EdgeDisjoint_ShouldShareRouter",
+			graph:        sharedRouterGraph,
+			source:       "S",
+			target:       "T",
+			disjointness: EdgeDisjoint,
+			// the routes can be split at M in two ways with the same total latency
+			expLatency: 6,
+		},
+		{
+			desc:         "SharedRouter_NodeDisjoint_ShouldAvoidRouter",
+			graph:        sharedRouterGraph,
+			source:       "S",
+			target:       "T",
+			disjointness: NodeDisjoint,
+			expFirst:     "S->M->T",
+			expSecond:    "S->C->T",
+			expLatency:   12,
+		},
+		{
+			desc: "ParallelLinks_EdgeDisjoint",
+			graph: map[string][]Node{
+				"S": {{"T", 1}, {"T", 3}},
+			},
+			source:       "S",
+			target:       "T",
+			disjointness: EdgeDisjoint,
+			expFirst:     "S->T",
+			expSecond:    "S->T",
+			expLatency:   4,
+		},
+		{
+			desc: "SingleRoute_ShouldFailWith_ErrNoDisjointPaths",
+			graph: map[string][]Node{
+				"S": {{"A", 1}, {"B", 1}},
+				"A": {{"M", 1}},
+				"B": {{"M", 1}},
+				"M": {{"T", 1}},
+			},
+			source:       "S",
+			target:       "T",
+			disjointness: NodeDisjoint,
+			expErr:       ErrNoDisjointPaths,
+		},
+		{
+			desc: "Unreachable_ShouldFailWith_ErrNoPath",
+			graph: map[string][]Node{
+				"S": {{"A", 1}},
+				"T": {},
+			},
+			source:       "S",
+			target:       "T",
+			disjointness: EdgeDisjoint,
+			expErr:       ErrNoPath,
+		},
+		{
+			desc:         "UnknownTarget_ShouldFailWith_ErrUnknownNode",
+			graph:        trapGraph,
+			source:       "S",
+			target:       "X",
+			disjointness: EdgeDisjoint,
+			expErr:       ErrUnknownNode,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			pair, err := FindDisjointPaths(tc.graph, tc.source, tc.target, tc.disjointness)
+			if tc.expErr != nil {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+
+			th.AssertNilError(t, err)
+			th.AssertEqualFloats(t, pair.Latency(), tc.expLatency)
+			if tc.expFirst != "" {
+				th.AssertEqualStrings(t, pair.First.String(), tc.expFirst)
+				th.AssertEqualStrings(t, pair.Second.String(), tc.expSecond)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package routing
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Graph is a compact, read-only representation of a routing topology.
+// Router ids are interned to dense indices and the links are stored in
+// compressed sparse row (CSR) layout: the links leaving router i are
+// targets[offsets[i]:offsets[i+1]] with the matching latencies.
+// Relaxing a link costs two slice reads instead of a map lookup.
+type Graph struct {
+	ids       []string         // dense index -> router id
+	index     map[string]int32 // router id -> dense index
+	offsets   []int            // len(ids)+1 offsets into targets and latencies
+	targets   []int32
+	latencies []float32
+}
+
+// link is a single directed link recorded by the GraphBuilder.
+type link struct {
+	from, to int32
+	latency  float32
+}
+
+// GraphBuilder collects routers and links and builds a Graph.
+// The links can be added in any order.
+type GraphBuilder struct {
+	ids   []string
+	index map[string]int32
+	links []link
+	err   error // first invalid link, reported by Build
+}
+
+// NewGraphBuilder creates an empty GraphBuilder.
+func NewGraphBuilder() *GraphBuilder {
+	return &GraphBuilder{index: make(map[string]int32)}
+}
+
+// AddNode interns the router id and returns its dense index.
+// Adding the same id twice returns the same index.
+func (b *GraphBuilder) AddNode(id string) int {
+	if i, ok := b.index[id]; ok {
+		return int(i)
+	}
+
+	i := int32(len(b.ids))
+	b.index[id] = i
+	b.ids = append(b.ids, id)
+
+	return int(i)
+}
+
+// AddLink adds a directed link between two routers, adding the routers if needed.
+// An invalid latency is reported by Build as ErrInvalidLatency.
+func (b *GraphBuilder) AddLink(from, to string, latency float32) {
+	if !isValidLatency(latency) && b.err == nil {
+		b.err = fmt.Errorf("%w: %v on %q->%q", ErrInvalidLatency, latency, from, to)
+	}
+
+	b.links = append(b.links, link{int32(b.AddNode(from)), int32(b.AddNode(to)), latency})
+}
+
+// Build creates the Graph. The links of each router keep the order they were added in.
+func (b *GraphBuilder) Build() (*Graph, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	n := len(b.ids)
+	g := &Graph{
+		ids:       b.ids,
+		index:     b.index,
+		offsets:   make([]int, n+1),
+		targets:   make([]int32, len(b.links)),
+		latencies: make([]float32, len(b.links)),
+	}
+
+	// counting sort of the links by their source router:
+	// count the links of each router, then turn the counts into offsets
+	for _, l := range b.links {
+		g.offsets[l.from+1]++
+	}
+	for i := 0; i < n; i++ {
+		g.offsets[i+1] += g.offsets[i]
+	}
+
+	next := slices.Clone(g.offsets[:n])
+	for _, l := range b.links {
+		g.targets[next[l.from]] = l.to
+		g.latencies[next[l.from]] = l.latency
+		next[l.from]++
+	}
+
+	return g, nil
+}
+
+// NewGraph converts the adjacency list form into a Graph.
+// Routers are interned in sorted order of the adjacency list keys,
+// so the same adjacency list always results in the same Graph.
+func NewGraph(adjacency map[string][]Node) (*Graph, error) {
+	keys := make([]string, 0, len(adjacency))
+	edges := 0
+	for id, neighbors := range adjacency {
+		keys = append(keys, id)
+		edges += len(neighbors)
+	}
+	slices.Sort(keys)
+
+	b := NewGraphBuilder()
+	b.links = make([]link, 0, edges)
+	for _, id := range keys {
+		b.AddNode(id)
+	}
+	for _, id := range keys {
+		for _, next := range adjacency[id] {
+			b.AddLink(id, next.Id, next.Latency)
+		}
+	}
+
+	return b.Build()
+}
+
+// Len returns the number of routers in the graph.
+func (g *Graph) Len() int { return len(g.ids) }
+
+// Links returns the number of links in the graph.
+func (g *Graph) Links() int { return len(g.targets) }
+
+// ID returns the router id of the given dense index.
+func (g *Graph) ID(i int) string { return g.ids[i] }
+
+// Index returns the dense index of the given router id.
+func (g *Graph) Index(id string) (int, bool) {
+	i, ok := g.index[id]
+	return int(i), ok
+}
+
+// links returns the range of the links leaving router u in targets and latencies.
+func (g *Graph) links(u int) (from, to int) {
+	return g.offsets[u], g.offsets[u+1]
+}
+
+// lookup returns the dense indices of source and target or ErrUnknownNode.
+func (g *Graph) lookup(source, target string) (int, int, error) {
+	s, ok := g.Index(source)
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: source %q", ErrUnknownNode, source)
+	}
+
+	t, ok := g.Index(target)
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: target %q", ErrUnknownNode, target)
+	}
+
+	return s, t, nil
+}
+
+// reverse returns the Graph with the direction of every link reversed.
+// The router indices stay the same.
+func (g *Graph) reverse() *Graph {
+	r := &Graph{
+		ids:       g.ids,
+		index:     g.index,
+		offsets:   make([]int, len(g.offsets)),
+		targets:   make([]int32, len(g.targets)),
+		latencies: make([]float32, len(g.latencies)),
+	}
+
+	// counting sort of the links by their target router
+	for _, v := range g.targets {
+		r.offsets[v+1]++
+	}
+	for i := 0; i < g.Len(); i++ {
+		r.offsets[i+1] += r.offsets[i]
+	}
+
+	next := slices.Clone(r.offsets[:g.Len()])
+	for u := range g.Len() {
+		from, to := g.links(u)
+		for l := from; l < to; l++ {
+			v := g.targets[l]
+			r.targets[next[v]] = int32(u)
+			r.latencies[next[v]] = g.latencies[l]
+			next[v]++
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,149 @@
+package routing
+
+import (
+	"math"
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestNewGraph(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		adjacency    map[string][]Node
+		expIds       []string
+		expTargets   map[string][]string
+		expLatencies map[string][]float32
+		expErr       error
+	}{
+		{
+			desc: "Success",
+			adjacency: map[string][]Node{
+				"C": {{"D", 30}},
+				"A": {{"B", 10}, {"C", 20}},
+				"B": {{"D", 15}},
+			},
+			expIds: []string{"A", "B", "C", "D"},
+			expTargets: map[string][]string{
+				"A": {"B", "C"},
+				"B": {"D"},
+				"C": {"D"},
+				"D": {},
+			},
+			expLatencies: map[string][]float32{
+				"A": {10, 20},
+				"B": {15},
+				"C": {30},
+				"D": {},
+			},
+		},
+		{
+			desc:         "EmptyAdjacency_ShouldSucceed",
+			adjacency:    map[string][]Node{},
+			expIds:       []string{},
+			expTargets:   map[string][]string{},
+			expLatencies: map[string][]float32{},
+		},
+		{
+			desc: "NegativeLatency_ShouldFailWith_ErrInvalidLatency",
+			adjacency: map[string][]Node{
+				"A": {{"B", -1}},
+			},
+			expErr: ErrInvalidLatency,
+		},
+		{
+			desc: "InfiniteLatency_ShouldFailWith_ErrInvalidLatency",
+			adjacency: map[string][]Node{
+				"A": {{"B", float32(math.Inf(1))}},
+			},
+			expErr: ErrInvalidLatency,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			g, err := NewGraph(tc.adjacency)
+			if tc.expErr != nil {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+			th.AssertNilError(t, err)
+
+			th.AssertEqualInts(t, g.Len(), len(tc.expIds))
+			for i, id := range tc.expIds {
+				th.AssertEqualStrings(t, g.ID(i), id)
+
+				idx, ok := g.Index(id)
+				if !ok {
+					t.Fatalf("exp %q to be interned", id)
+				}
+				th.AssertEqualInts(t, idx, i)
+
+				from, to := g.links(i)
+				targets := []string{}
+				for l := from; l < to; l++ {
+					targets = append(targets, g.ID(int(g.targets[l])))
+					th.AssertEqualFloats(t, g.latencies[l], tc.expLatencies[id][l-from])
+				}
+				th.AssertEqualStringSlices(t, targets, tc.expTargets[id])
+			}
+		})
+	}
+}
+
+func TestGraphBuilder(t *testing.T) {
+	// links of the same router are not added together
+	b := NewGraphBuilder()
+	b.AddLink("A", "B", 1)
+	b.AddLink("B", "C", 2)
+	b.AddLink("A", "C", 3)
+	b.AddNode("D")
+	th.AssertEqualInts(t, b.AddNode("A"), 0)
+
+	g, err := b.Build()
+	th.AssertNilError(t, err)
+	th.AssertEqualInts(t, g.Len(), 4)
+	th.AssertEqualInts(t, g.Links(), 3)
+
+	route, latency, err := g.FindMinimumLatencyPath([]string{"A"}, "A", "C")
+	th.AssertNilError(t, err)
+	th.AssertEqualStrings(t, route.String(), "A*->C")
+	th.AssertEqualFloats(t, latency, 1.5)
+
+	_, _, err = g.FindMinimumLatencyPath(nil, "A", "D")
+	th.AssertCorrectError(t, err, ErrNoPath)
+}
+
+// Compared to BenchmarkFindMinimumLatencyPath this one excludes
+// the conversion from the adjacency list, the Graph is built once.
+func BenchmarkGraphFindMinimumLatencyPath(b *testing.B) {
+	testCases := []struct {
+		desc  string
+		nodes int
+	}{
+		{
+			desc:  "10K_Routers",
+			nodes: 10_000,
+		},
+		{
+			desc:  "100K_Routers",
+			nodes: 100_000,
+		},
+	}
+
+	for _, tc := range testCases {
+		adjacency, compressionNodes := randomGraph(tc.nodes, 4)
+		g, err := NewGraph(adjacency)
+		if err != nil {
+			b.Fatal(err)
+		}
+		target := routerId(tc.nodes / 2)
+
+		b.Run(tc.desc, func(b *testing.B) {
+			for b.Loop() {
+				if _, _, err := g.FindMinimumLatencyPath(compressionNodes, routerId(0), target); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package routing
+
+import (
+	"container/heap"
+	"fmt"
+	"slices"
+)
+
+// path is a route through a Graph in dense indices: links[i] leads from nodes[i] to nodes[i+1].
+type path struct {
+	nodes      []int
+	links      []int
+	compressed int // position in links of the compressed link, -1 if none
+	latency    float32
+}
+
+type pathHeap []path
+
+// sort.Interface methods
+func (ph pathHeap) Len() int           { return len(ph) }
+func (ph pathHeap) Swap(i, j int)      { ph[i], ph[j] = ph[j], ph[i] }
+func (ph pathHeap) Less(i, j int) bool { return ph[i].latency < ph[j].latency }
+
+// heap.Interface methods
+func (ph *pathHeap) Push(x any) { *ph = append(*ph, x.(path)) }
+
+func (ph *pathHeap) Pop() any {
+	deref := *ph
+	l := len(deref)
+	last := deref[l-1]
+	*ph = deref[0 : l-1]
+
+	return last
+}
+
+// KShortestPaths finds up to k loopless routes from source to target ordered by their latency,
+// using Yen's algorithm. Compression can be applied once per route at any of the compressionNodes,
+// each route is reported with the compression at the place where it saves the most.
+// Routes differ in at least one link, the same links with compression at a different place are
+// not a separate route.
+//
+// Parameters:
+//   - graph: adjacency list representing the graph
+//   - compressionNodes: list of node identifiers that support compression
+//   - source: id of the starting node
+//   - target: id of the destination node
+//   - k: maximum number of routes
+//
+// Returns:
+//   - routes: up to k routes, fewer if there are not enough loopless routes
+//   - err: ErrUnknownNode, ErrInvalidLatency or ErrNoPath if no route can be computed
+func KShortestPaths(graph map[string][]Node, compressionNodes []string, source, target string, k int) ([]Route, error) {
+	g, err := NewGraph(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.KShortestPaths(compressionNodes, source, target, k)
+}
+
+// KShortestPaths is the Graph counterpart of the package level KShortestPaths.
+func (g *Graph) KShortestPaths(compressionNodes []string, source, target string, k int) ([]Route, error) {
+	s, t, err := g.lookup(source, target)
+	if err != nil {
+		return nil, err
+	}
+
+	factors := g.defaultCompressionFactors(compressionNodes)
+
+	if k <= 0 {
+		return []Route{}, nil
+	}
+
+	blockedNodes := make([]bool, g.Len())
+	blockedLinks := make([]bool, g.Links())
+
+	first, found := g.spurPath(factors, g.newPath(factors, s, nil), t, blockedNodes, blockedLinks)
+	if !found {
+		return nil, fmt.Errorf("%w: from %q to %q", ErrNoPath, source, target)
+	}
+	clear(blockedNodes)
+
+	paths := []path{first}
+	candidates := &pathHeap{}
+	// the same path can be found from different spur nodes
+	seen := map[string]bool{fmt.Sprint(first.links): true}
+
+	for len(paths) < k {
+		last := paths[len(paths)-1]
+
+		// deviate from the last path at each of its routers (the spur node)
+		for i := range last.links {
+			rootLinks := last.links[:i]
+
+			// the links already used to leave the spur node after the same root are excluded,
+			// otherwise the search would find one of the known paths again
+			for _, p := range paths {
+				if len(p.links) > i && slices.Equal(p.links[:i], rootLinks) {
+					blockedLinks[p.links[i]] = true
+				}
+			}
+			// the routers of the root are excluded to keep the path loopless
+			for _, u := range last.nodes[:i] {
+				blockedNodes[u] = true
+			}
+
+			candidate, found := g.spurPath(factors, g.newPath(factors, s, rootLinks), t, blockedNodes, blockedLinks)
+			if found && !seen[fmt.Sprint(candidate.links)] {
+				seen[fmt.Sprint(candidate.links)] = true
+				heap.Push(candidates, candidate)
+			}
+
+			clear(blockedNodes)
+			clear(blockedLinks)
+		}
+
+		if candidates.Len() == 0 {
+			break
+		}
+		paths = append(paths, heap.Pop(candidates).(path))
+	}
+
+	routes := make([]Route, len(paths))
+	for i, p := range paths {
+		routes[i] = g.pathRoute(factors, p)
+	}
+
+	return routes, nil
+}
+
+// spurPath finds the fastest path to target which starts with the root path
+// and continues without the blocked nodes and links. The spur node (the last router of the root)
+// gets blocked as well, so the search cannot return to it.
+func (g *Graph) spurPath(factors []float32, root path, target int, blockedNodes, blockedLinks []bool) (path, bool) {
+	spur := root.nodes[len(root.nodes)-1]
+	blockedNodes[spur] = true
+
+	search := newSearch(g, factors, 1)
+	search.blockedNodes, search.blockedLinks = blockedNodes, blockedLinks
+
+	// The compression can be spent in the root or saved for the rest of the path,
+	// the search continues from both states and keeps the better option.
+	search.seed(state{node: spur}, g.linksLatency(root.links))
+	if root.compressed >= 0 {
+		search.seed(state{spur, 1}, root.latency)
+	}
+
+	end, found := search.run(target)
+	if !found {
+		return path{}, false
+	}
+
+	// the links of the spur path in proper order, the starting state has no link
+	backwardPath := traceBack(search.prev, end)
+	links := slices.Clone(root.links)
+	for i := len(backwardPath) - 2; i >= 0; i-- {
+		links = append(links, search.vias[search.index(backwardPath[i])])
+	}
+
+	return g.newPath(factors, root.nodes[0], links), true
+}
+
+// newPath creates the path from source following the links,
+// with the compression applied on the link where it saves the most latency.
+func (g *Graph) newPath(factors []float32, source int, links []int) path {
+	p := path{
+		nodes:      []int{source},
+		links:      links,
+		compressed: -1,
+		latency:    g.linksLatency(links),
+	}
+
+	var saving float32
+	for i, l := range links {
+		if factor := factors[p.nodes[i]]; factor != noCompression {
+			if s := g.latencies[l] - g.latencies[l]*factor; s > saving {
+				saving, p.compressed = s, i
+			}
+		}
+		p.nodes = append(p.nodes, int(g.targets[l]))
+	}
+	p.latency -= saving
+
+	return p
+}
+
+// linksLatency returns the total latency of the links without compression.
+func (g *Graph) linksLatency(links []int) float32 {
+	var latency float32
+	for _, l := range links {
+		latency += g.latencies[l]
+	}
+
+	return latency
+}
+
+// pathRoute converts the path to a Route.
+func (g *Graph) pathRoute(factors []float32, p path) Route {
+	route := Route{Hops: make([]Hop, 0, len(p.links))}
+
+	var cumulative float32
+	for i, l := range p.links {
+		hop := Hop{
+			From:    g.ID(p.nodes[i]),
+			To:      g.ID(p.nodes[i+1]),
+			Latency: g.latencies[l],
+		}
+		if i == p.compressed {
+			hop.Latency *= factors[p.nodes[i]]
+			hop.Compressed = true
+		}
+		cumulative += hop.Latency
+		hop.Cumulative = cumulative
+
+		route.Hops = append(route.Hops, hop)
+	}
+
+	return route
+}
@@ -0,0 +1,138 @@
+package routing
+
+import (
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestKShortestPaths(t *testing.T) {
+	// https://en.wikipedia.org/wiki/Yen%27s_algorithm#Example
+	yenGraph := map[string][]Node{
+		"C": {{"D", 3}, {"E", 2}},
+		"D": {{"F", 4}},
+		"E": {{"D", 1}, {"F", 2}, {"G", 3}},
+		"F": {{"G", 2}, {"H", 1}},
+		"G": {{"H", 2}},
+		"H": {},
+	}
+
+	testCases := []struct {
+		desc             string
+		graph            map[string][]Node
+		compressionNodes []string
+		source           string
+		target           string
+		k                int
+		expPaths         []string
+		expLatencies     []float32
+	}{
+		{
+			desc:         "Success_WithoutCompression",
+			graph:        yenGraph,
+			source:       "C",
+			target:       "H",
+			k:            2,
+			expPaths:     []string{"C->E->F->H", "C->E->G->H"},
+			expLatencies: []float32{5, 7},
+		},
+		{
+			desc:         "Success_AllPaths",
+			graph:        yenGraph,
+			source:       "C",
+			target:       "H",
+			k:            10,
+			expLatencies: []float32{5, 7, 8, 8, 8, 11, 11},
+		},
+		{
+			desc:             "Success_WithCompression_ShouldReorderPaths",
+			graph:            yenGraph,
+			compressionNodes: []string{"E"},
+			source:           "C",
+			target:           "H",
+			k:                4,
+			expPaths:         []string{"C->E*->F->H", "C->E*->G->H", "C->E*->F->G->H", "C->E*->D->F->H"},
+			expLatencies:     []float32{4, 5.5, 7, 7.5},
+		},
+		{
+			desc: "FewerPaths_ThanK_ShouldReturnAll",
+			graph: map[string][]Node{
+				"A": {{"B", 10}, {"C", 20}},
+				"B": {{"D", 15}},
+				"C": {{"D", 30}},
+				"D": {},
+			},
+			compressionNodes: []string{"A", "B", "C"},
+			source:           "A",
+			target:           "D",
+			k:                5,
+			expPaths:         []string{"A->B*->D", "A->C*->D"},
+			expLatencies:     []float32{17.5, 35},
+		},
+		{
+			desc: "CyclicGraph_ShouldReturn_LooplessPaths",
+			graph: map[string][]Node{
+				"A": {{"B", 1}},
+				"B": {{"A", 1}, {"C", 1}},
+				"C": {{"B", 1}, {"D", 1}},
+				"D": {},
+			},
+			source:       "A",
+			target:       "D",
+			k:            3,
+			expPaths:     []string{"A->B->C->D"},
+			expLatencies: []float32{3},
+		},
+		{
+			desc: "SourceIsTarget_ShouldReturn_EmptyRoute",
+			graph: map[string][]Node{
+				"A": {{"B", 1}},
+			},
+			source:       "A",
+			target:       "A",
+			k:            3,
+			expPaths:     []string{""},
+			expLatencies: []float32{0},
+		},
+		{
+			desc:         "ZeroK_ShouldReturn_NoRoutes",
+			graph:        yenGraph,
+			source:       "C",
+			target:       "H",
+			k:            0,
+			expPaths:     []string{},
+			expLatencies: []float32{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			routes, err := KShortestPaths(tc.graph, tc.compressionNodes, tc.source, tc.target, tc.k)
+			th.AssertNilError(t, err)
+
+			th.AssertEqualInts(t, len(routes), len(tc.expLatencies))
+
+			paths := make([]string, len(routes))
+			for i, route := range routes {
+				paths[i] = route.String()
+				th.AssertEqualFloats(t, route.Latency(), tc.expLatencies[i])
+			}
+			// paths with equal latency can be returned in any order
+			if tc.expPaths != nil {
+				th.AssertEqualStringSlices(t, paths, tc.expPaths)
+			}
+		})
+	}
+}
+
+func TestKShortestPaths_Errors(t *testing.T) {
+	graph := map[string][]Node{
+		"A": {{"B", 1}},
+		"C": {},
+	}
+
+	_, err := KShortestPaths(graph, nil, "A", "C", 2)
+	th.AssertCorrectError(t, err, ErrNoPath)
+
+	_, err = KShortestPaths(graph, nil, "X", "C", 2)
+	th.AssertCorrectError(t, err, ErrUnknownNode)
+}
@@ -0,0 +1,7 @@
+//go:build !routingdebug
+
+package routing
+
+// debug enables the expensive self checks, e.g. the admissibility of A* heuristics.
+// Build with -tags routingdebug to enable it.
+const debug = false
@@ -0,0 +1,101 @@
+package routing
+
+// Hop is a single link of a Route.
+type Hop struct {
+	From       string
+	To         string
+	Latency    float32 // latency of the link, after compression
+	Cumulative float32 // total latency from the source up to To
+	Compressed bool    // whether compression was applied at From
+}
+
+// Route is a path through the network ordered from source to target.
+// An empty Route means the target is the source itself or it is not reachable.
+type Route struct {
+	Hops []Hop
+}
+
+// newRoute builds a Route from the backward path of states returned by traceBack.
+// The per-hop latencies come from the links, linkLatency returns the latency of the link
+// used from prev to next without compression, and the factor of the router applies when compressed.
+func newRoute(g *Graph, factors []float32, backwardPath []state, linkLatency func(prev, next state) float32) Route {
+	if len(backwardPath) < 2 {
+		return Route{}
+	}
+
+	hops := make([]Hop, 0, len(backwardPath)-1)
+	var cumulative float32
+	for i := len(backwardPath) - 1; i > 0; i-- {
+		prev, next := backwardPath[i], backwardPath[i-1]
+		hop := Hop{
+			From:       g.ID(prev.node),
+			To:         g.ID(next.node),
+			Latency:    linkLatency(prev, next),
+			Compressed: isCompressedAt(prev, next),
+		}
+		if hop.Compressed {
+			hop.Latency *= factors[prev.node]
+		}
+		cumulative += hop.Latency
+		hop.Cumulative = cumulative
+		hops = append(hops, hop)
+	}
+
+	return Route{Hops: hops}
+}
+
+// Nodes returns the ids of the routers along the route, including source and target.
+func (r Route) Nodes() []string {
+	if len(r.Hops) == 0 {
+		return []string{}
+	}
+
+	nodes := make([]string, 0, len(r.Hops)+1)
+	for _, hop := range r.Hops {
+		nodes = append(nodes, hop.From)
+	}
+
+	return append(nodes, r.Hops[len(r.Hops)-1].To)
+}
+
+// CompressedAt returns the ids of the routers where compression was applied, in route order.
+func (r Route) CompressedAt() []string {
+	nodes := []string{}
+	for _, hop := range r.Hops {
+		if hop.Compressed {
+			nodes = append(nodes, hop.From)
+		}
+	}
+
+	return nodes
+}
+
+// Latency returns the total latency of the route.
+func (r Route) Latency() float32 {
+	if len(r.Hops) == 0 {
+		return 0
+	}
+
+	return r.Hops[len(r.Hops)-1].Cumulative
+}
+
+// String formats the route as A->B*->C, where '*' marks the routers
+// where compression was applied.
+func (r Route) String() string {
+	if len(r.Hops) == 0 {
+		return ""
+	}
+
+	// prettyPrintPath expects the path in backward order
+	backwardPath := make([]string, 0, len(r.Hops)+1)
+	backwardPath = append(backwardPath, r.Hops[len(r.Hops)-1].To)
+	for i := len(r.Hops) - 1; i >= 0; i-- {
+		id := r.Hops[i].From
+		if r.Hops[i].Compressed {
+			id += "*"
+		}
+		backwardPath = append(backwardPath, id)
+	}
+
+	return prettyPrintPath(backwardPath)
+}
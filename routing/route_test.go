@@ -0,0 +1,115 @@
+package routing
+
+import (
+	"testing"
+
+	th "developers-challenge/pkg/testhelpers"
+)
+
+func TestNewRoute(t *testing.T) {
+	graph, err := NewGraph(map[string][]Node{
+		"A": {{"B", 10}},
+		"B": {{"D", 15}},
+		"D": {},
+	})
+	th.AssertNilError(t, err)
+
+	// dense indices of the routers, the keys are interned in sorted order
+	a, b, d := 0, 1, 2
+	factors := []float32{0.5, 0.5, noCompression}
+	linkLatency := func(prev, next state) float32 {
+		from, to := graph.links(prev.node)
+		for l := from; l < to; l++ {
+			if int(graph.targets[l]) == next.node {
+				return graph.latencies[l]
+			}
+		}
+		t.Fatalf("no link from %d to %d", prev.node, next.node)
+		return 0
+	}
+
+	testCases := []struct {
+		desc            string
+		backwardPath    []state
+		expOut          string
+		expNodes        []string
+		expCompressedAt []string
+		expLatencies    []float32
+		expLatency      float32
+	}{
+		{
+			desc:            "Success_WithCompression",
+			backwardPath:    []state{{d, 1}, {b, 0}, {a, 0}},
+			expOut:          "A->B*->D",
+			expNodes:        []string{"A", "B", "D"},
+			expCompressedAt: []string{"B"},
+			expLatencies:    []float32{10, 7.5},
+			expLatency:      17.5,
+		},
+		{
+			desc:            "Success_WithMultipleCompressions",
+			backwardPath:    []state{{d, 2}, {b, 1}, {a, 0}},
+			expOut:          "A*->B*->D",
+			expNodes:        []string{"A", "B", "D"},
+			expCompressedAt: []string{"A", "B"},
+			expLatencies:    []float32{5, 7.5},
+			expLatency:      12.5,
+		},
+		{
+			desc:            "Success_WithoutCompression",
+			backwardPath:    []state{{d, 0}, {b, 0}, {a, 0}},
+			expOut:          "A->B->D",
+			expNodes:        []string{"A", "B", "D"},
+			expCompressedAt: []string{},
+			expLatencies:    []float32{10, 15},
+			expLatency:      25,
+		},
+		{
+			desc:            "EmptyPath_ShouldReturn_EmptyRoute",
+			backwardPath:    []state{},
+			expOut:          "",
+			expNodes:        []string{},
+			expCompressedAt: []string{},
+			expLatencies:    []float32{},
+			expLatency:      0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			route := newRoute(graph, factors, tc.backwardPath, linkLatency)
+			th.AssertEqualStrings(t, route.String(), tc.expOut)
+			th.AssertEqualStringSlices(t, route.Nodes(), tc.expNodes)
+			th.AssertEqualStringSlices(t, route.CompressedAt(), tc.expCompressedAt)
+			th.AssertEqualFloats(t, route.Latency(), tc.expLatency)
+			th.AssertEqualInts(t, len(route.Hops), len(tc.expLatencies))
+			for i, hop := range route.Hops {
+				th.AssertEqualFloats(t, hop.Latency, tc.expLatencies[i])
+			}
+		})
+	}
+}
+
+func TestRoute_HopLatencyAfterLongPrefix(t *testing.T) {
+	graph, err := NewGraph(map[string][]Node{
+		"A": {{"B", 1e7}},
+		"B": {{"C", 0.3}},
+		"C": {{"D", 0.25}},
+		"D": {},
+	})
+	th.AssertNilError(t, err)
+
+	// float32 cannot tell 1e7 from 1e7+0.3, the hops still keep the latency of their links
+	for _, find := range []func([]string, string, string) (Route, float32, error){
+		graph.FindMinimumLatencyPath,
+		graph.FindMinimumLatencyPathBidirectional,
+	} {
+		route, dist, err := find([]string{"A"}, "A", "D")
+		th.AssertNilError(t, err)
+		th.AssertEqualInts(t, len(route.Hops), 3)
+		th.AssertEqualFloats(t, route.Hops[0].Latency, 5e6)
+		th.AssertEqualFloats(t, route.Hops[1].Latency, 0.3)
+		th.AssertEqualFloats(t, route.Hops[2].Latency, 0.25)
+		// the running sum ends at the latency the finder returned
+		th.AssertEqualFloats(t, route.Hops[2].Cumulative, dist)
+	}
+}
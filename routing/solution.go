@@ -2,13 +2,31 @@ package routing
 
 import (
 	"container/heap"
+	"errors"
 	"fmt"
+	"math"
 	"strings"
 )
 
+var (
+	ErrUnknownNode              = errors.New("node is not part of the graph")
+	ErrNoPath                   = errors.New("target is not reachable from source")
+	ErrInvalidLatency           = errors.New("latency must be a non-negative finite number")
+	ErrInvalidCompressionFactor = errors.New("compression factor must be a non-negative finite number")
+	ErrNoDisjointPaths          = errors.New("there is no pair of disjoint paths from source to target")
+	ErrInadmissibleHeuristic    = errors.New("heuristic overestimates the latency to the target")
+)
+
+// state is a vertex of the search space: a router (its dense index in the Graph)
+// together with the number of compressions already applied on the way to it.
+type state struct {
+	node int
+	used int
+}
+
 type neighbor struct {
-	id      string
-	latency float32 // distance to the neighbor (edge weight)
+	state   state
+	latency float32 // priority: distance from the source to the neighbor state (plus the A* estimate)
 }
 
 type minHeap []neighbor
@@ -30,15 +48,19 @@ func (mh *minHeap) Pop() any {
 	return last
 }
 
+// DefaultCompressionFactor is the latency factor of the links leaving
+// a compression node when no custom factor is provided.
+const DefaultCompressionFactor float32 = 0.5
+
 type Node struct {
 	Id      string
 	Latency float32 // distance
 }
 
 // FindMinimumLatencyPath computes the path with the minimum total latency between the source and target nodes
-// in the given graph. The graph is represented as an adjacency list. The function takes into account a list
-// of compressionNodes, which may affect path selection or latency calculations.
-// It returns the optimal path as a string and the total latency as a float32.
+// in the given graph. The graph is represented as an adjacency list. Compression can be applied once per path
+// at any of the compressionNodes, halving the latency of the link leaving that node.
+// It returns the optimal route and the total latency as a float32.
 //
 // Parameters:
 //   - graph: adjacency list representing the graph
@@ -47,66 +69,407 @@ type Node struct {
 //   - target: id of the destination node
 //
 // Returns:
-//   - path: formatted path from source to target (e.g. A->B->C)
+//   - route: hops from source to target, formatted as A->B*->C where '*' marks the compression node
 //   - dist: the total latency of the path
-func FindMinimumLatencyPath(graph map[string][]Node, compressionNodes []string, source, target string) (path string, dist float32) {
-	// distances map will store the total distance from source router to each router
-	distances := make(map[string]float32)
+//   - err: ErrUnknownNode, ErrInvalidLatency or ErrNoPath if the path cannot be computed
+func FindMinimumLatencyPath(graph map[string][]Node, compressionNodes []string, source, target string) (route Route, dist float32, err error) {
+	g, err := NewGraph(graph)
+	if err != nil {
+		return Route{}, 0, err
+	}
+
+	return g.FindMinimumLatencyPath(compressionNodes, source, target)
+}
 
-	// distance to source(self) is 0
-	distances[source] = 0
+// FindMinimumLatencyPathWithBudget computes the path with the minimum total latency between the source
+// and target nodes, allowing compression at up to budget nodes along the path. Each compression node
+// has its own factor, which multiplies the latency of the link leaving that node.
+//
+// Parameters:
+//   - graph: adjacency list representing the graph
+//   - compressionFactors: latency factor of each node that supports compression (e.g. 0.5 halves the latency)
+//   - source: id of the starting node
+//   - target: id of the destination node
+//   - budget: maximum number of compressions per path, a negative budget is treated as 0
+//
+// Returns:
+//   - route: hops from source to target, Route.CompressedAt lists the nodes where compression was applied
+//   - dist: the total latency of the path
+//   - err: ErrUnknownNode, ErrInvalidLatency, ErrInvalidCompressionFactor or ErrNoPath if the path cannot be computed
+func FindMinimumLatencyPathWithBudget(
+	graph map[string][]Node,
+	compressionFactors map[string]float32,
+	source, target string,
+	budget int,
+) (route Route, dist float32, err error) {
+	g, err := NewGraph(graph)
+	if err != nil {
+		return Route{}, 0, err
+	}
+
+	return g.FindMinimumLatencyPathWithBudget(compressionFactors, source, target, budget)
+}
+
+// FindMinimumLatencyPath is the Graph counterpart of the package level FindMinimumLatencyPath.
+func (g *Graph) FindMinimumLatencyPath(compressionNodes []string, source, target string) (Route, float32, error) {
+	return g.findMinimumLatencyPath(g.defaultCompressionFactors(compressionNodes), source, target, 1)
+}
+
+// FindMinimumLatencyPathWithBudget is the Graph counterpart of the package level FindMinimumLatencyPathWithBudget.
+func (g *Graph) FindMinimumLatencyPathWithBudget(
+	compressionFactors map[string]float32,
+	source, target string,
+	budget int,
+) (Route, float32, error) {
+	factors, err := g.compressionFactors(compressionFactors)
+	if err != nil {
+		return Route{}, 0, err
+	}
+
+	return g.findMinimumLatencyPath(factors, source, target, budget)
+}
+
+// findMinimumLatencyPath runs the search with the factors indexed by the dense router index.
+func (g *Graph) findMinimumLatencyPath(factors []float32, source, target string, budget int) (Route, float32, error) {
+	s, t, err := g.lookup(source, target)
+	if err != nil {
+		return Route{}, 0, err
+	}
+
+	search := newSearch(g, factors, budget)
+	search.seed(state{node: s}, 0)
+
+	end, found := search.run(t)
+	if !found {
+		return Route{}, 0, fmt.Errorf("%w: from %q to %q", ErrNoPath, source, target)
+	}
+
+	return search.route(end), search.distance(end), nil
+}
+
+// compressionFactors converts the factors keyed by router id to a slice indexed by the dense router index.
+// Routers without compression get noCompression. Unknown routers are ignored, they cannot be part of any path.
+func (g *Graph) compressionFactors(compressionFactors map[string]float32) ([]float32, error) {
+	factors := g.noCompressionFactors()
+	for id, factor := range compressionFactors {
+		if !isValidLatency(factor) {
+			return nil, fmt.Errorf("%w: %v at %q", ErrInvalidCompressionFactor, factor, id)
+		}
+		if i, ok := g.Index(id); ok {
+			factors[i] = factor
+		}
+	}
+
+	return factors, nil
+}
+
+// defaultCompressionFactors is compressionFactors with DefaultCompressionFactor for every compression node.
+func (g *Graph) defaultCompressionFactors(compressionNodes []string) []float32 {
+	factors := g.noCompressionFactors()
+	for _, id := range compressionNodes {
+		if i, ok := g.Index(id); ok {
+			factors[i] = DefaultCompressionFactor
+		}
+	}
+
+	return factors
+}
+
+// noCompressionFactors returns the factors of a graph without compression nodes.
+func (g *Graph) noCompressionFactors() []float32 {
+	factors := make([]float32, g.Len())
+	for i := range factors {
+		factors[i] = noCompression
+	}
+
+	return factors
+}
+
+// noCompression marks the routers which do not support compression.
+// Valid compression factors are never negative.
+const noCompression float32 = -1
+
+// search holds the per-query data of Dijkstra's (or A*) algorithm over the
+// (router, compressions used) states of a Graph. Each router is represented
+// once per number of used compressions (0..budget), so routes with different
+// remaining budget to the same router do not override each other.
+// The states are stored in flat slices, state{u, c} is at index u*layers+c.
+type search struct {
+	graph   *Graph
+	factors []float32 // compression factor of each router or noCompression
+	layers  int       // budget + 1
+
+	// distances will store the total distance from source router to each state
+	distances []float32
 
 	// Traces will keep track every time distance is added/updated,
-	// holding the information from where neighbor router is reached.
-	// traces[neighbor] = previous_router
-	traces := make(map[string]string)
+	// holding the index of the state from where neighbor state is reached, -1 if none.
+	traces []int
+
+	// vias hold the index of the link used to reach each state, -1 if none
+	vias []int
+
+	// blockedNodes and blockedLinks are excluded from the search, nil if nothing is blocked
+	blockedNodes []bool
+	blockedLinks []bool
+
+	// backward searches from the target in the reversed graph of a bidirectional query,
+	// the distance of a state is then the latency from it to the target
+	backward bool
+
+	// opposite is the search from the other end of a bidirectional query, nil otherwise
+	opposite *search
+	meeting  *meeting
+
+	// heuristic returns the A* lower bound of the latency from a router to the target,
+	// nil for Dijkstra. The estimates are cached, NaN marks the ones not computed yet.
+	heuristic func(node int) float32
+	estimates []float32
+	minFactor float32 // the lowest compression factor, at most 1
+
+	// The heap is ordered by priority - the distance plus the estimate for A*.
+	// Every improvement pushes a new entry and the older entries of the state become stale.
+	// Stale entries are skipped when popped (lazy deletion), so with Dijkstra
+	// every state is expanded exactly once even in cyclic graphs.
+	minHeap minHeap
+}
+
+func newSearch(g *Graph, factors []float32, budget int) *search {
+	layers := max(budget, 0) + 1
+	size := g.Len() * layers
+
+	s := &search{
+		graph:     g,
+		factors:   factors,
+		layers:    layers,
+		distances: make([]float32, size),
+		traces:    make([]int, size),
+		vias:      make([]int, size),
+	}
+	for i := range size {
+		s.distances[i] = float32(math.Inf(1))
+		s.traces[i] = -1
+		s.vias[i] = -1
+	}
+
+	return s
+}
+
+func (s *search) index(st state) int { return st.node*s.layers + st.used }
+
+func (s *search) state(i int) state { return state{i / s.layers, i % s.layers} }
 
-	// setup neighbors min heap
-	minHeap := &minHeap{}
-	heap.Push(minHeap, neighbor{source, 0})
+// distance returns the shortest known distance from the source to the state.
+func (s *search) distance(st state) float32 { return s.distances[s.index(st)] }
 
+// prev returns the state from which st was reached.
+func (s *search) prev(st state) (state, bool) {
+	i := s.traces[s.index(st)]
+	if i < 0 {
+		return state{}, false
+	}
+
+	return s.state(i), true
+}
+
+// useHeuristic turns the search to A*, h must never overestimate the latency without compression.
+func (s *search) useHeuristic(h func(node int) float32) {
+	s.heuristic = h
+	s.estimates = make([]float32, s.graph.Len())
+	for i := range s.estimates {
+		s.estimates[i] = float32(math.NaN())
+	}
+
+	s.minFactor = 1
+	for _, factor := range s.factors {
+		if factor != noCompression {
+			s.minFactor = min(s.minFactor, factor)
+		}
+	}
+}
+
+// estimate returns the lower bound of the latency from the state to the target, 0 for Dijkstra.
+// Every link can be compressed at most once and by at most minFactor, so while there is budget left
+// the remaining latency is at least minFactor times the estimate without compression.
+func (s *search) estimate(st state) float32 {
+	if s.heuristic == nil {
+		return 0
+	}
+
+	h := s.estimates[st.node]
+	if math.IsNaN(float64(h)) {
+		h = s.heuristic(st.node)
+		// a negative or NaN estimate is no information at all
+		if !(h > 0) {
+			h = 0
+		}
+		s.estimates[st.node] = h
+	}
+
+	if st.used+1 < s.layers {
+		return h * s.minFactor
+	}
+
+	return h
+}
+
+// priority returns the heap key of the state.
+func (s *search) priority(st state) float32 {
+	return s.distance(st) + s.estimate(st)
+}
+
+// seed sets a starting state of the search.
+// There can be more than one, e.g. the same router with different number of used compressions.
+func (s *search) seed(st state, distance float32) {
+	s.distances[s.index(st)] = distance
+	heap.Push(&s.minHeap, neighbor{st, s.priority(st)})
+}
+
+// relax updates the distance to next if it can be reached faster through prev using the link
+func (s *search) relax(prev, next state, link int, distanceToNext float32) {
+	i := s.index(next)
+
+	// Update if the current distance is less then the stored one,
+	// there is no recorded distance to the neighbor if the stored one is +Inf
+	if distanceToNext < s.distances[i] {
+		s.distances[i] = distanceToNext
+		s.traces[i] = s.index(prev)
+		s.vias[i] = link
+
+		// push the improved neighbor to the heap, the previous entry becomes stale
+		heap.Push(&s.minHeap, neighbor{next, s.priority(next)})
+
+		// every path through next is now checked with the best known rest of it
+		if s.opposite != nil {
+			s.meeting.update(next, distanceToNext+s.opposite.distance(next))
+		}
+	}
+}
+
+// run expands the states in order of their priority until a state of the target router is popped.
+// The target can be reached with different number of compressions, the first popped one is the fastest.
+func (s *search) run(target int) (state, bool) {
 	// loop until no neighbors are left in the heap
-	for minHeap.Len() > 0 {
+	for {
 		// get the "nearest" neighbor
-		router := heap.Pop(minHeap).(neighbor)
-
-		// loop through the neighbors and update the shortest distance
-		for _, next := range graph[router.id] {
-			distanceToNext := distances[router.id] + next.Latency
-			_, ok := distances[next.Id]
-			// Update if:
-			// a) there is no recorded distance to the neighbor
-			// b) the current distance is less then the stored one
-			if !ok || distanceToNext < distances[next.Id] {
-				distances[next.Id] = distanceToNext
-				traces[next.Id] = router.id
-			}
-
-			// push new neighbor to the heap,
-			// so we can keep the loop going until all routers are traversed
-			heap.Push(minHeap, neighbor{next.Id, distances[next.Id]})
+		router, ok := s.next()
+		if !ok {
+			return state{}, false
+		}
+
+		// the distance to the target is final, no need to explore further
+		if router.node == target {
+			return router, true
+		}
+
+		s.expand(router)
+	}
+}
+
+// top returns the priority of the next state without removing it, dropping the stale entries on the way.
+// Returns false when the heap is empty.
+func (s *search) top() (float32, bool) {
+	for s.minHeap.Len() > 0 {
+		entry := s.minHeap[0]
+
+		// skip stale entries, the state was reached faster after the entry was pushed
+		if entry.latency > s.priority(entry.state) {
+			heap.Pop(&s.minHeap)
+			continue
+		}
+
+		return entry.latency, true
+	}
+
+	return 0, false
+}
+
+// next removes and returns the state with the lowest priority.
+// Returns false when the heap is empty.
+func (s *search) next() (state, bool) {
+	if _, ok := s.top(); !ok {
+		return state{}, false
+	}
+
+	return heap.Pop(&s.minHeap).(neighbor).state, true
+}
+
+// expand relaxes all links leaving the router.
+func (s *search) expand(router state) {
+	distance := s.distance(router)
+	factor := s.factors[router.node]
+
+	// loop through the neighbors and update the shortest distance
+	from, to := s.graph.links(router.node)
+	for l := from; l < to; l++ {
+		next, latency := int(s.graph.targets[l]), s.graph.latencies[l]
+		if s.blockedLinks != nil && s.blockedLinks[l] || s.blockedNodes != nil && s.blockedNodes[next] {
+			continue
+		}
+
+		// send the data as it is
+		s.relax(router, state{next, router.used}, l, distance+latency)
+
+		switch {
+		// compress the data before sending it, if there is budget left
+		case !s.backward && factor != noCompression && router.used+1 < s.layers:
+			s.relax(router, state{next, router.used + 1}, l, distance+latency*factor)
+
+		// backwards the link leads from next, so the data could be compressed there
+		// if one of the compressions was not used yet
+		case s.backward && s.factors[next] != noCompression && router.used > 0:
+			s.relax(router, state{next, router.used - 1}, l, distance+latency*s.factors[next])
 		}
 	}
+}
+
+// distancesFrom returns the latency without compression from the source to every router,
+// +Inf for the routers which are not reachable.
+func (g *Graph) distancesFrom(source int) []float32 {
+	factors := g.noCompressionFactors()
+
+	search := newSearch(g, factors, 0)
+	search.seed(state{node: source}, 0)
+	// there is no router -1, so the whole graph is explored
+	search.run(-1)
 
-	path = prettyPrintPath(traceBack(traces, target))
-	dist = distances[target]
-	return path, dist
+	// with a budget of 0 there is a single state per router
+	return search.distances
+}
+
+// route rebuilds the Route from the source to the end state.
+func (s *search) route(end state) Route {
+	return newRoute(s.graph, s.factors, traceBack(s.prev, end), func(_, next state) float32 {
+		return s.linkLatency(next)
+	})
+}
+
+// linkLatency returns the latency without compression of the link the state was reached with.
+func (s *search) linkLatency(st state) float32 {
+	return s.graph.latencies[s.vias[s.index(st)]]
+}
+
+// isValidLatency reports whether latency is a non-negative finite number.
+// Dijkstra's algorithm does not work with negative edge weights.
+func isValidLatency(latency float32) bool {
+	l := float64(latency)
+	return l >= 0 && !math.IsInf(l, 1) && !math.IsNaN(l)
 }
 
 // traceBack will rebuild the path from "source" to "target"
-// using the traces map.
+// using the traces, prev returns from where the given element is reached.
 // Returns the path in backward order from "target" to "source"
-func traceBack(traces map[string]string, target string) []string {
+func traceBack[T any](traces func(T) (T, bool), target T) []T {
 	// No path to target
-	if _, ok := traces[target]; !ok {
-		return []string{}
+	if _, ok := traces(target); !ok {
+		return []T{}
 	}
 
 	// restore backward path
-	backwardPath := []string{target}
+	backwardPath := []T{target}
 
 	for {
-		prev, ok := traces[target]
+		prev, ok := traces(target)
 		if ok {
 			backwardPath = append(backwardPath, prev)
 			target = prev
@@ -118,6 +481,12 @@ func traceBack(traces map[string]string, target string) []string {
 	return backwardPath
 }
 
+// isCompressedAt reports whether compression was applied on the link from prev to next.
+// Compression is applied on the link leaving the router, so next has one more compression used.
+func isCompressedAt(prev, next state) bool {
+	return next.used > prev.used
+}
+
 // prettyPrintPath using the backwardPath array will
 // reverse the path in the format: A->B->C
 // Returns formatted path in proper order
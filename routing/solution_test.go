@@ -1,6 +1,9 @@
 package routing
 
 import (
+	"fmt"
+	"math"
+	"math/rand/v2"
 	"testing"
 
 	th "developers-challenge/pkg/testhelpers"
@@ -34,27 +37,27 @@ func TestFindMinimumLatencyPath(t *testing.T) {
 			expPath:    "A->B->D",
 			expLatency: 25,
 		},
-		// {
-		// 	desc: "Success_With_3Routers_WithCompression",
-		// 	graph: func() map[string][]Node {
-		// 		graph := make(map[string][]Node)
+		{
+			desc: "Success_With_3Routers_WithCompression",
+			graph: func() map[string][]Node {
+				graph := make(map[string][]Node)
 
-		// 		graph["A"] = []Node{{"B", 10}, {"C", 20}}
-		// 		graph["B"] = []Node{{"D", 15}}
-		// 		graph["C"] = []Node{{"D", 30}}
-		// 		graph["D"] = []Node{}
+				graph["A"] = []Node{{"B", 10}, {"C", 20}}
+				graph["B"] = []Node{{"D", 15}}
+				graph["C"] = []Node{{"D", 30}}
+				graph["D"] = []Node{}
 
-		// 		return graph
+				return graph
 
-		// 	}(),
-		// 	source:           "A",
-		// 	target:           "D",
-		// 	compressionNodes: []string{"B", "C"},
-		// 	expPath:          "A->B->D",
-		// 	expLatency:       17.5,
-		// },
+			}(),
+			source:           "A",
+			target:           "D",
+			compressionNodes: []string{"B", "C"},
+			expPath:          "A->B*->D",
+			expLatency:       17.5,
+		},
 		{
-			desc: "Success_With_3Routers_WithoutCompression",
+			desc: "Success_With_3Routers_WithCompressionAtSource",
 			graph: func() map[string][]Node {
 				graph := make(map[string][]Node)
 
@@ -69,11 +72,29 @@ func TestFindMinimumLatencyPath(t *testing.T) {
 			compressionNodes: []string{"A", "B"},
 			source:           "A",
 			target:           "D",
-			expPath:          "A->C->D",
-			expLatency:       10,
+			expPath:          "A*->C->D",
+			expLatency:       6,
+		},
+		{
+			desc: "Success_With_3Routers_WithoutCompressionNodes",
+			graph: func() map[string][]Node {
+				graph := make(map[string][]Node)
+
+				graph["A"] = []Node{{"B", 4}, {"C", 8}}
+				graph["B"] = []Node{{"E", 6}}
+				graph["C"] = []Node{{"D", 2}}
+				graph["D"] = []Node{{"E", 10}}
+
+				return graph
+
+			}(),
+			source:     "A",
+			target:     "D",
+			expPath:    "A->C->D",
+			expLatency: 10,
 		},
 		{
-			desc: "Success_With_4Routers_WithoutCompression",
+			desc: "Success_With_4Routers_WithCompression",
 			graph: func() map[string][]Node {
 				graph := make(map[string][]Node)
 
@@ -90,19 +111,265 @@ func TestFindMinimumLatencyPath(t *testing.T) {
 			compressionNodes: []string{"A", "B"},
 			source:           "A",
 			target:           "D",
-			expPath:          "A->B->F->D",
-			expLatency:       6,
+			expPath:          "A*->B->F->D",
+			expLatency:       4,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			actRoute, actLatency, err := FindMinimumLatencyPath(tc.graph, tc.compressionNodes, tc.source, tc.target)
+			th.AssertNilError(t, err)
+			th.AssertEqualStrings(t, actRoute.String(), tc.expPath)
+			th.AssertEqualFloats(t, actLatency, tc.expLatency)
+		})
+	}
+}
+
+func TestFindMinimumLatencyPath_Errors(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		graph  map[string][]Node
+		source string
+		target string
+		expErr error
+	}{
+		{
+			desc: "UnknownSource_ShouldFailWith_ErrUnknownNode",
+			graph: map[string][]Node{
+				"A": {{"B", 10}},
+			},
+			source: "X",
+			target: "B",
+			expErr: ErrUnknownNode,
+		},
+		{
+			desc: "UnknownTarget_ShouldFailWith_ErrUnknownNode",
+			graph: map[string][]Node{
+				"A": {{"B", 10}},
+			},
+			source: "A",
+			target: "X",
+			expErr: ErrUnknownNode,
+		},
+		{
+			desc: "DisconnectedTarget_ShouldFailWith_ErrNoPath",
+			graph: map[string][]Node{
+				"A": {{"B", 10}},
+				"C": {{"A", 10}},
+			},
+			source: "A",
+			target: "C",
+			expErr: ErrNoPath,
+		},
+		{
+			desc: "NegativeLatency_ShouldFailWith_ErrInvalidLatency",
+			graph: map[string][]Node{
+				"A": {{"B", -10}},
+			},
+			source: "A",
+			target: "B",
+			expErr: ErrInvalidLatency,
+		},
+		{
+			desc: "NaNLatency_ShouldFailWith_ErrInvalidLatency",
+			graph: map[string][]Node{
+				"A": {{"B", float32(math.NaN())}},
+			},
+			source: "A",
+			target: "B",
+			expErr: ErrInvalidLatency,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, _, err := FindMinimumLatencyPath(tc.graph, nil, tc.source, tc.target)
+			th.AssertNotNilError(t, err)
+			th.AssertCorrectError(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestFindMinimumLatencyPath_SourceIsTarget(t *testing.T) {
+	graph := map[string][]Node{
+		"A": {{"B", 10}},
+	}
+
+	route, latency, err := FindMinimumLatencyPath(graph, nil, "A", "A")
+	th.AssertNilError(t, err)
+	th.AssertEqualInts(t, len(route.Hops), 0)
+	th.AssertEqualFloats(t, latency, 0)
+}
+
+func TestFindMinimumLatencyPath_CyclicGraph(t *testing.T) {
+	graph := map[string][]Node{
+		"A": {{"B", 1}, {"A", 1}},
+		"B": {{"C", 1}, {"A", 1}},
+		"C": {{"A", 1}, {"B", 1}, {"D", 10}},
+		"D": {{"C", 1}},
+	}
+
+	route, latency, err := FindMinimumLatencyPath(graph, []string{"C"}, "A", "D")
+	th.AssertNilError(t, err)
+	th.AssertEqualStrings(t, route.String(), "A->B->C*->D")
+	th.AssertEqualFloats(t, latency, 7)
+}
+
+func TestFindMinimumLatencyPathWithBudget(t *testing.T) {
+	// A -> B -> C -> D is the long way, A -> D is the direct but slow link
+	graph := make(map[string][]Node)
+	graph["A"] = []Node{{"B", 10}, {"D", 26}}
+	graph["B"] = []Node{{"C", 10}}
+	graph["C"] = []Node{{"D", 10}}
+	graph["D"] = []Node{}
+
+	testCases := []struct {
+		desc               string
+		compressionFactors map[string]float32
+		budget             int
+		expPath            string
+		expLatency         float32
+		expCompressedAt    []string
+		expErr             error
+	}{
+		{
+			desc:               "ZeroBudget_ShouldIgnoreCompression",
+			compressionFactors: map[string]float32{"A": 0.5, "B": 0.5, "C": 0.5},
+			budget:             0,
+			expPath:            "A->D",
+			expLatency:         26,
+			expCompressedAt:    []string{},
+		},
+		{
+			desc:               "SingleCompression",
+			compressionFactors: map[string]float32{"A": 0.5, "B": 0.5, "C": 0.5},
+			budget:             1,
+			expPath:            "A*->D",
+			expLatency:         13,
+			expCompressedAt:    []string{"A"},
+		},
+		{
+			desc:               "MultipleCompressions_ShouldPreferLongerPath",
+			compressionFactors: map[string]float32{"A": 0.5, "B": 0.25, "C": 0.25},
+			budget:             3,
+			expPath:            "A*->B*->C*->D",
+			expLatency:         10,
+			expCompressedAt:    []string{"A", "B", "C"},
+		},
+		{
+			desc:               "BudgetLimit_ShouldUseBestFactors",
+			compressionFactors: map[string]float32{"A": 0.5, "B": 0.1, "C": 0.1},
+			budget:             2,
+			expPath:            "A->B*->C*->D",
+			expLatency:         12,
+			expCompressedAt:    []string{"B", "C"},
+		},
+		{
+			desc:               "NegativeFactor_ShouldFailWith_ErrInvalidCompressionFactor",
+			compressionFactors: map[string]float32{"A": -0.5},
+			budget:             1,
+			expErr:             ErrInvalidCompressionFactor,
+		},
+		{
+			desc:            "NoCompressionNodes",
+			budget:          2,
+			expPath:         "A->D",
+			expLatency:      26,
+			expCompressedAt: []string{},
 		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			actPath, actLatency := FindMinimumLatencyPath(tc.graph, tc.compressionNodes, tc.source, tc.target)
-			th.AssertEqualStrings(t, actPath, tc.expPath)
+			actRoute, actLatency, err := FindMinimumLatencyPathWithBudget(graph, tc.compressionFactors, "A", "D", tc.budget)
+			if tc.expErr != nil {
+				th.AssertCorrectError(t, err, tc.expErr)
+				return
+			}
+
+			th.AssertNilError(t, err)
+			th.AssertEqualStrings(t, actRoute.String(), tc.expPath)
 			th.AssertEqualFloats(t, actLatency, tc.expLatency)
+			th.AssertEqualStringSlices(t, actRoute.CompressedAt(), tc.expCompressedAt)
+		})
+	}
+}
+
+// The benchmarks run on random sparse cyclic graphs with growing size.
+// Besides ns/op they report the time per (V+E)*log(V) unit,
+// which should stay roughly constant as the graph grows.
+func BenchmarkFindMinimumLatencyPath(b *testing.B) {
+	testCases := []struct {
+		desc   string
+		nodes  int
+		degree int
+	}{
+		{
+			desc:   "1K_Routers",
+			nodes:  1_000,
+			degree: 4,
+		},
+		{
+			desc:   "10K_Routers",
+			nodes:  10_000,
+			degree: 4,
+		},
+		{
+			desc:   "100K_Routers",
+			nodes:  100_000,
+			degree: 4,
+		},
+	}
+
+	for _, tc := range testCases {
+		graph, compressionNodes := randomGraph(tc.nodes, tc.degree)
+		// the farthest router from the source in the ring, so most of the graph is explored
+		target := routerId(tc.nodes / 2)
+
+		b.Run(tc.desc, func(b *testing.B) {
+			for b.Loop() {
+				if _, _, err := FindMinimumLatencyPath(graph, compressionNodes, routerId(0), target); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			v, e := float64(tc.nodes), float64(tc.nodes*tc.degree)
+			nsPerOp := float64(b.Elapsed().Nanoseconds()) / float64(b.N)
+			b.ReportMetric(nsPerOp/((v+e)*math.Log2(v)), "ns/(V+E)logV")
 		})
 	}
 }
 
+// randomGraph builds a cyclic graph with a ring through all routers,
+// so every router is reachable, plus random extra links with integer latencies.
+// Every 10th router supports compression.
+func randomGraph(nodes, degree int) (map[string][]Node, []string) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+
+	return randomWeightedGraph(rnd, nodes, degree, func() float32 { return float32(rnd.IntN(100) + 1) })
+}
+
+// randomWeightedGraph is randomGraph with the latencies returned by latency.
+func randomWeightedGraph(rnd *rand.Rand, nodes, degree int, latency func() float32) (map[string][]Node, []string) {
+	graph := make(map[string][]Node, nodes)
+	compressionNodes := []string{}
+	for i := 0; i < nodes; i++ {
+		id := routerId(i)
+		graph[id] = append(graph[id], Node{routerId((i + 1) % nodes), latency()})
+		for j := 1; j < degree; j++ {
+			graph[id] = append(graph[id], Node{routerId(rnd.IntN(nodes)), latency()})
+		}
+
+		if i%10 == 0 {
+			compressionNodes = append(compressionNodes, id)
+		}
+	}
+
+	return graph, compressionNodes
+}
+
+func routerId(i int) string {
+	return fmt.Sprintf("R%d", i)
+}
+
 func TestTraceBack(t *testing.T) {
 	testCases := []struct {
 		desc   string
@@ -192,12 +459,20 @@ func TestTraceBack(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			act := traceBack(tc.traces, tc.target)
+			act := traceBack(lookup(tc.traces), tc.target)
 			th.AssertEqualStringSlices(t, act, tc.expOut)
 		})
 	}
 }
 
+// lookup adapts a traces map to the function expected by traceBack.
+func lookup[T comparable](traces map[T]T) func(T) (T, bool) {
+	return func(target T) (T, bool) {
+		prev, ok := traces[target]
+		return prev, ok
+	}
+}
+
 func TestPrettyPrintPath(t *testing.T) {
 	testCases := []struct {
 		desc         string